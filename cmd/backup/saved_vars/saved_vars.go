@@ -3,6 +3,8 @@ package cmd
 import (
 	"archive/zip"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/dyoung522/esotools/lib/eso"
@@ -25,7 +27,9 @@ func execute(cmd *cobra.Command, args []string) {
 	}
 }
 
-func BackupSavedVars(AppFs afero.Fs) error {
+// BackupSavedVars creates a ZIP file in the current directory containing every SavedVariables file, or only the
+// named files (e.g. "MyAddon.lua") if any are given.
+func BackupSavedVars(AppFs afero.Fs, names ...string) error {
 	var err error
 	verbosity := viper.GetInt("verbosity")
 
@@ -33,6 +37,15 @@ func BackupSavedVars(AppFs afero.Fs) error {
 	archiveTime := fmt.Sprintf("%d%02d%02d%02d%02d%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
 	archiveFileName := fmt.Sprintf("saved_variables_%s.zip", archiveTime)
 
+	// Don't overwrite a backup made within the same second (the suffix keeps backups sorting by age)
+	for n := 2; ; n++ {
+		if exists, _ := afero.Exists(AppFs, archiveFileName); !exists {
+			break
+		}
+
+		archiveFileName = fmt.Sprintf("saved_variables_%s_%02d.zip", archiveTime, n)
+	}
+
 	saveVarFiles, err := eso.FindSavedVars(AppFs)
 	if err != nil {
 		fmt.Println(err)
@@ -55,6 +68,10 @@ func BackupSavedVars(AppFs afero.Fs) error {
 	defer zipWriter.Close()
 
 	for _, file := range saveVarFiles {
+		if len(names) > 0 && !slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(name, file.Name()) }) {
+			continue
+		}
+
 		if verbosity >= 2 {
 			fmt.Printf("Adding %s to %s\n", file.Name(), archiveFileName)
 		}
@@ -3,11 +3,13 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/dyoung522/esotools/lib/eso"
 	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -21,20 +23,48 @@ var (
 )
 
 var flags struct {
-	optional bool
+	optional     bool
+	whatIfRemove []string
 }
 
+// Exit codes returned by the check, so scripts can tell the failure classes apart
+const (
+	exitMissing   = 1 // A required dependency is not installed
+	exitError     = 2 // The AddOns could not be read
+	exitOutdated  = 3 // A required dependency is installed, but older than the version required
+	exitCycle     = 4 // AddOns depend upon each other in a loop
+	exitMalformed = 5 // A required dependency has a malformed version constraint
+)
+
 // ListAddOnsCmd represents the addons command
 var CheckAddOnsCmd = &cobra.Command{
 	Use:   "addons",
 	Short: "Checks dependencies for ESO AddOns",
-	Long:  `Checks AddOns installed in the ESO AddOns directory, and reports any errors`,
-	Run:   execute,
+	Long: `Checks AddOns installed in the ESO AddOns directory, and reports any errors
+
+AddOns which depend upon each other in a loop (including loops through optional dependencies) are reported as errors,
+as the game won't load any of them. AddOns which list themselves as a dependency are reported as warnings.
+
+Dependencies with a malformed version constraint (anything other than a whole number after >=, such as "LibGPS>=abc"
+or "LibGPS<71") are reported as errors, as their version can't be checked.
+
+Exits with status 1 if any required dependencies are missing, 3 if they're all installed but some are older than required,
+5 if some have a malformed version constraint, or 4 if the only errors are circular dependencies.
+
+With --what-if-remove, nothing is checked. Instead, it reports what would happen if the given AddOns were removed:
+every AddOn which would stop loading, every AddOn which would lose an optional dependency, and every SavedVariables file
+which would no longer belong to an AddOn. Exits with status 1 if any AddOns would stop loading.`,
+	Example: `  esotools check addons --what-if-remove LibAddonMenu-2.0,LibGPS`,
+	Run:     execute,
 }
 
 func execute(cmd *cobra.Command, args []string) {
 	var errors, warnings map[string][]string
+	var outdatedErrors, outdatedWarnings map[string][]string
+	var malformedErrors, malformedWarnings map[string][]string
 	var missingDependencies []string
+	var outdatedDependencies []eso.Dependency
+	var malformedDependencies []error
 	var dependencyArray = [2][]string{}
 	var verbosity = viper.GetInt("verbosity")
 
@@ -44,16 +74,25 @@ func execute(cmd *cobra.Command, args []string) {
 		for _, e := range errs {
 			fmt.Println(e)
 		}
-		os.Exit(2)
+		os.Exit(exitError)
 	}
 
 	errors = make(map[string][]string)
 	warnings = make(map[string][]string)
+	outdatedErrors = make(map[string][]string)
+	outdatedWarnings = make(map[string][]string)
+	malformedErrors = make(map[string][]string)
+	malformedWarnings = make(map[string][]string)
 
 	if viper.GetBool("noColor") {
 		pterm.DisableColor()
 	}
 
+	if len(flags.whatIfRemove) > 0 {
+		whatIfRemove(&addons, flags.whatIfRemove)
+		return
+	}
+
 	// Check each addon for dependencies, we use Keys() because it's sorted
 	for _, key := range addons.Keys() {
 		addon := addons[key]
@@ -86,9 +125,28 @@ func execute(cmd *cobra.Command, args []string) {
 				}
 			}
 
-			missingDependencies = checkDependencies(&addons, dependencies)
+			missingDependencies, outdatedDependencies, malformedDependencies = checkDependencies(&addons, dependencies)
+
+			for _, err := range malformedDependencies {
+				if first {
+					malformedErrors[key] = append(malformedErrors[key], err.Error())
+				} else {
+					malformedWarnings[key] = append(malformedWarnings[key], err.Error())
+				}
+			}
+
+			for _, outdatedDependency := range outdatedDependencies {
+				name := addons.Get(outdatedDependency.Name).Key()
+				needs := fmt.Sprintf("%s needs %s", key, outdatedDependency.Constraint())
 
-			if len(missingDependencies) > 0 {
+				if first {
+					outdatedErrors[name] = append(outdatedErrors[name], needs)
+				} else {
+					outdatedWarnings[name] = append(outdatedWarnings[name], needs)
+				}
+			}
+
+			if len(missingDependencies) > 0 || len(outdatedDependencies) > 0 || len(malformedDependencies) > 0 {
 				for _, missingDependency := range missingDependencies {
 					if missingDependency == "" {
 						continue
@@ -122,13 +180,40 @@ func execute(cmd *cobra.Command, args []string) {
 		fmt.Println()
 	}
 
+	graph := eso.NewDependencyGraph(addons)
+	cycles := graph.Cycles()
+
+	printShadowed(&addons)
+	printSelfReferences(graph.SelfReferences())
+
 	if len(warnings) > 0 {
 		printErrors(&warnings, "optional")
 	}
 
-	if len(errors) > 0 {
+	if len(outdatedWarnings) > 0 {
+		printOutdated(&addons, &outdatedWarnings, "optional")
+	}
+
+	if len(malformedWarnings) > 0 {
+		printMalformed(&malformedWarnings, "optional")
+	}
+
+	if len(errors) > 0 || len(outdatedErrors) > 0 || len(malformedErrors) > 0 || len(cycles) > 0 {
 		printErrors(&errors, "required")
-		os.Exit(1)
+		printOutdated(&addons, &outdatedErrors, "required")
+		printMalformed(&malformedErrors, "required")
+		printCycles(cycles)
+
+		switch {
+		case len(errors) > 0:
+			os.Exit(exitMissing)
+		case len(outdatedErrors) > 0:
+			os.Exit(exitOutdated)
+		case len(malformedErrors) > 0:
+			os.Exit(exitMalformed)
+		default:
+			os.Exit(exitCycle)
+		}
 	}
 
 	green.Printf("\nAll %d Required Dependencies Ok\n", len(addons))
@@ -162,28 +247,194 @@ func printErrors(errors *map[string][]string, dependencyType string) {
 	}
 }
 
-func checkDependencies(addons *eso.AddOns, dependencies []string) []string {
-	var missingDependencies = []string{}
+// Reports AddOns that ship more than one manifest, and which one the game will use
+func printShadowed(addons *eso.AddOns) {
+	for _, key := range addons.Keys() {
+		addon := (*addons)[key]
 
-	for _, dependency := range dependencies {
-		if len(eso.DependencyName(dependency)) == 0 {
+		if addon.Shadowed() == "" || addon.Manifest() == nil {
 			continue
 		}
 
-		dependencyName := eso.DependencyName(dependency)[0]
-		if dependencyName == "" {
+		fmt.Printf(
+			"%s has multiple manifests, using %s (ignoring %s)\n",
+			yellow.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", key),
+			cyan.Sprint(filepath.Base(addon.Manifest().Path)),
+			blue.Sprint(addon.Shadowed()),
+		)
+	}
+}
+
+func printOutdated(addons *eso.AddOns, outdated *map[string][]string, dependencyType string) {
+	var color = pterm.NewStyle(pterm.FgRed)
+	var keys = []string{}
+
+	if dependencyType == "optional" {
+		color = pterm.NewStyle(pterm.FgYellow)
+	}
+
+	for k := range *outdated {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		installed := addons.Get(key).AddOnVersion
+		if installed == "" {
+			installed = "without an AddOnVersion"
+		}
+
+		fmt.Printf(
+			"%s is an outdated %s dependency, installed %s, %s\n",
+			color.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", key),
+			color.Sprint(dependencyType),
+			cyan.Add(*pterm.Bold.ToStyle()).Sprint(installed),
+			blue.Sprint(pterm.DefaultParagraph.WithMaxWidth(80).Sprint(strings.Join((*outdated)[key], ", "))),
+		)
+	}
+}
+
+// Reports dependencies whose version constraint can't be parsed, by the AddOn which lists them
+func printMalformed(malformed *map[string][]string, dependencyType string) {
+	var color = pterm.NewStyle(pterm.FgRed)
+	var keys = []string{}
+
+	if dependencyType == "optional" {
+		color = pterm.NewStyle(pterm.FgYellow)
+	}
+
+	for k := range *malformed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, problem := range (*malformed)[key] {
+			fmt.Printf(
+				"%s has a malformed %s dependency, %s\n",
+				color.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", key),
+				color.Sprint(dependencyType),
+				blue.Sprint(problem),
+			)
+		}
+	}
+}
+
+// Reports AddOns which depend upon each other in a loop, none of which the game will load
+func printCycles(cycles []eso.DependencyCycle) {
+	for _, cycle := range cycles {
+		fmt.Printf(
+			"%s is a circular dependency between %s -> %s\n",
+			red.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", cycle.AddOns[0]),
+			cyan.Add(*pterm.Bold.ToStyle()).Sprintf("%d %-6s", len(cycle.AddOns), eso.Pluralize("AddOn", len(cycle.AddOns))),
+			blue.Sprint(pterm.DefaultParagraph.WithMaxWidth(80).Sprint(cycle)),
+		)
+	}
+}
+
+// Reports AddOns which list themselves as a dependency
+func printSelfReferences(edges []eso.GraphEdge) {
+	for _, edge := range edges {
+		fmt.Printf(
+			"%s lists itself as a %s dependency\n",
+			yellow.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", edge.From),
+			yellow.Sprint(edge.Type),
+		)
+	}
+}
+
+// Reports what would stop loading if the given AddOns were removed
+func whatIfRemove(addons *eso.AddOns, names []string) {
+	impact, err := addons.RemovalImpact(afero.NewOsFs(), names...)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(exitError)
+	}
+
+	blue.Println("Removing would delete:")
+	for _, folder := range impact.Folders {
+		fmt.Printf("  %s\n", filepath.Join(eso.AddOnsPath(), folder))
+	}
+	fmt.Println()
+
+	for _, addon := range impact.Fallbacks {
+		fmt.Printf(
+			"%s would load the copy in %s instead (AddOnVersion %s)\n",
+			yellow.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", addon.Key()),
+			blue.Sprint(addon.Dir()),
+			cyan.Sprint(addon.AddOnVersion),
+		)
+	}
+
+	for _, failure := range impact.Broken {
+		reason := failure.String()
+		if failure.Cause != "" && failure.Cause != failure.Dependency {
+			reason += blue.Sprintf(" (because of %s)", failure.Cause)
+		}
+
+		fmt.Printf("%s would stop loading, %s\n", red.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", failure.Key), reason)
+	}
+
+	for _, lost := range impact.Degraded {
+		fmt.Printf(
+			"%s would lose its optional dependency on %s\n",
+			yellow.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", lost.Key),
+			cyan.Sprint(lost.Dependency),
+		)
+	}
+
+	for _, savedVars := range impact.SavedVars {
+		fmt.Printf("%s would no longer belong to any AddOn\n", yellow.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", savedVars))
+	}
+
+	fmt.Println()
+
+	if len(impact.Broken) > 0 {
+		red.Printf(
+			"%d %s would stop loading\n",
+			len(impact.Broken),
+			eso.Pluralize("AddOn", len(impact.Broken)),
+		)
+		os.Exit(exitMissing)
+	}
+
+	green.Println("Nothing else would stop loading")
+}
+
+// Returns the names of any dependencies which aren't installed, any which are installed but don't meet their version
+// constraint, and the errors of any whose version constraint is malformed
+func checkDependencies(addons *eso.AddOns, dependencies []string) ([]string, []eso.Dependency, []error) {
+	var missingDependencies = []string{}
+	var outdatedDependencies = []eso.Dependency{}
+	var malformedDependencies = []error{}
+
+	for _, entry := range dependencies {
+		dependency, err := eso.ParseDependency(entry)
+		if err != nil {
+			malformedDependencies = append(malformedDependencies, err)
+		}
+
+		if dependency.Name == "" {
 			continue
 		}
 
 		// Check if the dependency exists
-		if _, exists := addons.Find(dependencyName); !exists {
-			missingDependencies = append(missingDependencies, dependencyName)
+		installed, exists := addons.Find(dependency.Name)
+		if !exists {
+			missingDependencies = append(missingDependencies, dependency.Name)
+			continue
+		}
+
+		// Check if the installed version is new enough
+		if !dependency.SatisfiedBy(installed) {
+			outdatedDependencies = append(outdatedDependencies, dependency)
 		}
 	}
 
-	return missingDependencies
+	return missingDependencies, outdatedDependencies, malformedDependencies
 }
 
 func init() {
 	CheckAddOnsCmd.Flags().BoolVarP(&flags.optional, "optional", "o", false, "Warn if optional dependencies aren't installed as well")
+	CheckAddOnsCmd.Flags().StringSliceVarP(&flags.whatIfRemove, "what-if-remove", "", []string{}, "Reports what would stop loading if the given AddOns were removed (comma separated)")
 }
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+	cyan   = pterm.NewStyle(pterm.FgCyan)
+	blue   = pterm.NewStyle(pterm.FgBlue)
+)
+
+// CheckAPICmd represents the api command
+var CheckAPICmd = &cobra.Command{
+	Use:   "api",
+	Short: "Reports ESO AddOns which are out of date",
+	Long: `Reports AddOns whose newest declared APIVersion is older than the current game API version,
+which is what the game uses to flag an AddOn as "Out of date".
+
+The current API version may be given with --api-version, or set as "api_version" in your config file.
+Otherwise, the highest API version declared by any installed AddOn is used.
+
+Exits with status 1 if any AddOns are out of date.`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var outOfDate []eso.AddOn
+	var verbosity = viper.GetInt("verbosity")
+
+	addons, errs := eso.Run()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		os.Exit(2)
+	}
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	currentAPIVersion := viper.GetInt("api_version")
+	if currentAPIVersion == 0 {
+		currentAPIVersion = addons.LatestAPIVersion()
+
+		if verbosity >= 1 {
+			fmt.Printf("No API version configured, using the highest declared by any AddOn (%d)\n", currentAPIVersion)
+		}
+	}
+
+	for _, key := range addons.Keys() {
+		addon := addons[key]
+
+		if addon.IsOutOfDate(currentAPIVersion) {
+			outOfDate = append(outOfDate, addon)
+		}
+	}
+
+	if len(outOfDate) == 0 {
+		green.Printf("All %d AddOns support API version %d\n", len(addons), currentAPIVersion)
+		return
+	}
+
+	for _, addon := range outOfDate {
+		latest := "no APIVersion"
+		if addon.LatestAPIVersion() > 0 {
+			latest = fmt.Sprintf("API %d", addon.LatestAPIVersion())
+		}
+
+		fmt.Printf(
+			"%s is out of date, declares %s\n",
+			yellow.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", addon.Key()),
+			blue.Sprint(latest),
+		)
+	}
+
+	fmt.Println()
+	cyan.Printf(
+		"%d %s out of date for API version %d\n",
+		len(outOfDate),
+		eso.Pluralize("AddOn", len(outOfDate)),
+		currentAPIVersion,
+	)
+
+	os.Exit(1)
+}
+
+func init() {
+	CheckAPICmd.Flags().IntP("api-version", "a", 0, "The current game API version (defaults to the highest declared by any installed AddOn)")
+	err := viper.BindPFlag("api_version", CheckAPICmd.Flags().Lookup("api-version"))
+	if err != nil {
+		panic(err)
+	}
+}
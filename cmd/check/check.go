@@ -2,6 +2,12 @@ package cmd
 
 import (
 	sub1 "github.com/dyoung522/esotools/cmd/check/addons"
+	sub4 "github.com/dyoung522/esotools/cmd/check/api"
+	sub5 "github.com/dyoung522/esotools/cmd/check/duplicates"
+	sub6 "github.com/dyoung522/esotools/cmd/check/layout"
+	sub8 "github.com/dyoung522/esotools/cmd/check/loadorder"
+	sub3 "github.com/dyoung522/esotools/cmd/check/manifests"
+	sub7 "github.com/dyoung522/esotools/cmd/check/orphans"
 	sub2 "github.com/dyoung522/esotools/cmd/check/saved_vars"
 	"github.com/spf13/cobra"
 )
@@ -15,6 +21,12 @@ var CheckCmd = &cobra.Command{
 func init() {
 	CheckCmd.AddCommand(sub1.CheckAddOnsCmd)
 	CheckCmd.AddCommand(sub2.CheckSavedVarsCmd)
+	CheckCmd.AddCommand(sub3.CheckManifestsCmd)
+	CheckCmd.AddCommand(sub4.CheckAPICmd)
+	CheckCmd.AddCommand(sub5.CheckDuplicatesCmd)
+	CheckCmd.AddCommand(sub6.CheckLayoutCmd)
+	CheckCmd.AddCommand(sub7.CheckOrphansCmd)
+	CheckCmd.AddCommand(sub8.CheckLoadOrderCmd)
 
 	// Here you will define your flags and configuration settings.
 
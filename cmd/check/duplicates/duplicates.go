@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	backup bool
+	clean  bool
+	dryRun bool
+	json   bool
+}
+
+var (
+	red     = pterm.NewStyle(pterm.FgRed)
+	caution = pterm.NewStyle(pterm.BgRed, pterm.FgYellow, pterm.Bold)
+	yellow  = pterm.NewStyle(pterm.FgYellow)
+	green   = pterm.NewStyle(pterm.FgGreen)
+	cyan    = pterm.NewStyle(pterm.FgCyan)
+	blue    = pterm.NewStyle(pterm.FgBlue)
+)
+
+// CheckDuplicatesCmd represents the duplicates command
+var CheckDuplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Reports AddOns which are installed more than once",
+	Long: `Reports every AddOn with more than one installed copy, showing where each copy lives, its AddOnVersion, and which copy the game will load.
+
+Ignored copies which aren't bundled inside another AddOn are suggested for removal.
+Optionally, you can remove them with the --clean flag.`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+	var removable []eso.DuplicateCopy
+
+	addons, errs := eso.Run()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		os.Exit(2)
+	}
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	duplicates := addons.Duplicates()
+
+	if flags.json {
+		output, err := eso.DuplicatesToJson(duplicates)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+
+		fmt.Println(string(output))
+		return
+	}
+
+	if len(duplicates) == 0 {
+		green.Println("No duplicate AddOns found")
+		return
+	}
+
+	for _, duplicate := range duplicates {
+		fmt.Printf("%s (%d copies)\n", cyan.Add(*pterm.Bold.ToStyle()).Sprint(duplicate.Key), len(duplicate.Copies))
+
+		for _, copy := range duplicate.Copies {
+			var status, note string
+
+			switch {
+			case copy.Loaded:
+				status = green.Sprint("loaded ")
+			case copy.Removable:
+				status = yellow.Sprint("ignored")
+				note = red.Sprint(" <- suggest removing")
+			case copy.Parent == "":
+				status = yellow.Sprint("ignored")
+				note = blue.Sprint(" (contains AddOns which are loaded)")
+			default:
+				status = yellow.Sprint("ignored")
+				note = blue.Sprintf(" (bundled with %s)", copy.Parent)
+			}
+
+			fmt.Printf("  %s AddOnVersion %-6s %s%s\n", status, versionString(copy), copy.Dir, note)
+		}
+
+		removable = append(removable, duplicate.Removable()...)
+	}
+
+	fmt.Println()
+	yellow.Printf(
+		"Found %d duplicated %s, %d %s can be removed\n",
+		len(duplicates), eso.Pluralize("AddOn", len(duplicates)),
+		len(removable), eso.Pluralize("copy", len(removable)),
+	)
+
+	if flags.clean && len(removable) > 0 {
+		clean(AppFs, removable)
+	}
+}
+
+func clean(AppFs afero.Fs, removable []eso.DuplicateCopy) {
+	var removePrompt = "Remove the suggested copies?"
+
+	if flags.dryRun {
+		removePrompt += " [dry-run enabled, no destructive actions will be taken]"
+	}
+
+	if result, _ := pterm.DefaultInteractiveConfirm.Show(removePrompt); !result {
+		return
+	}
+
+	if !flags.backup && !flags.dryRun {
+		savePrompt := caution.Sprint("This opperation is destructive, do you want to make a backup first?")
+
+		if result, _ := pterm.DefaultInteractiveConfirm.Show(savePrompt); result {
+			flags.backup = true
+		}
+	}
+
+	if flags.backup {
+		dirs := []string{}
+		for _, copy := range removable {
+			dirs = append(dirs, copy.Dir)
+		}
+
+		if _, err := eso.BackupAddOns(AppFs, dirs...); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	for _, copy := range removable {
+		path := filepath.Join(eso.AddOnsPath(), copy.Dir)
+
+		if flags.dryRun {
+			yellow.Printf("Would have removed: %q\n", path)
+		} else {
+			fmt.Println("Removing:", path)
+			if err := AppFs.RemoveAll(path); err != nil {
+				red.Printf("Error removing %s: %s\n", copy.Dir, err)
+			}
+		}
+	}
+}
+
+func versionString(copy eso.DuplicateCopy) string {
+	if copy.AddOnVersion == "" {
+		return "(none)"
+	}
+
+	return copy.AddOnVersion
+}
+
+func init() {
+	CheckDuplicatesCmd.Flags().BoolVarP(&flags.backup, "backup", "", false, "Performs a backup prior to any destructive actions")
+	CheckDuplicatesCmd.Flags().BoolVarP(&flags.clean, "clean", "", false, "Removes the copies suggested for removal")
+	CheckDuplicatesCmd.Flags().BoolVarP(&flags.dryRun, "dry-run", "", false, "Shows what changes would be made without actually making them. Use this to double-check before using --clean")
+	CheckDuplicatesCmd.Flags().BoolVarP(&flags.json, "json", "j", false, "Print out the report in JSON format")
+	CheckDuplicatesCmd.MarkFlagsMutuallyExclusive("json", "clean")
+}
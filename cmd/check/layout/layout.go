@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	backup bool
+	fix    bool
+	dryRun bool
+}
+
+var (
+	red     = pterm.NewStyle(pterm.FgRed)
+	caution = pterm.NewStyle(pterm.BgRed, pterm.FgYellow, pterm.Bold)
+	yellow  = pterm.NewStyle(pterm.FgYellow)
+	green   = pterm.NewStyle(pterm.FgGreen)
+	cyan    = pterm.NewStyle(pterm.FgCyan)
+)
+
+// CheckLayoutCmd represents the layout command
+var CheckLayoutCmd = &cobra.Command{
+	Use:   "layout",
+	Short: "Checks for mis-extracted ESO AddOn folders",
+	Long: `Reports AddOn folders which the game will not load because they were extracted incorrectly,
+such as AddOns/Foo/Foo/Foo.txt, AddOns/Foo-master/Foo.txt or AddOns/Foo 1.2/Foo.txt.
+Optionally, you can move them into the correct AddOns/<Name>/<Name>.txt layout with the --fix flag.`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+	var fixable []eso.MisplacedAddOn
+	var verbosity = viper.GetInt("verbosity")
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	if verbosity >= 2 {
+		cmd.Println("Checking AddOn folders")
+	}
+
+	misplaced, err := eso.FindMisplacedAddOns(AppFs)
+	if err != nil {
+		cmd.Println(err)
+		os.Exit(2)
+	}
+
+	if len(misplaced) == 0 {
+		green.Println("No mis-extracted AddOns found")
+		return
+	}
+
+	yellow.Printf("Found %d mis-extracted %s\n", len(misplaced), eso.Pluralize("AddOn", len(misplaced)))
+
+	for _, addon := range misplaced {
+		fmt.Printf("- %s", cyan.Sprint(addon))
+
+		if addon.Fixable() {
+			fixable = append(fixable, addon)
+			fmt.Println()
+		} else {
+			red.Printf(" [cannot fix: %s]\n", addon.Conflict)
+		}
+	}
+
+	if !flags.fix || len(fixable) == 0 {
+		return
+	}
+
+	var fixPrompt = "Move the above AddOns into place?"
+
+	if flags.dryRun {
+		fixPrompt += " [dry-run enabled, no destructive actions will be taken]"
+	}
+
+	if result, _ := pterm.DefaultInteractiveConfirm.Show(fixPrompt); !result {
+		return
+	}
+
+	if !flags.backup && !flags.dryRun {
+		savePrompt := caution.Sprint("This opperation is destructive, do you want to make a backup first?")
+
+		if result, _ := pterm.DefaultInteractiveConfirm.Show(savePrompt); result {
+			flags.backup = true
+		}
+	}
+
+	if flags.backup {
+		dirs := []string{}
+		for _, addon := range fixable {
+			dirs = append(dirs, addon.Dir)
+		}
+
+		if _, err = eso.BackupAddOns(AppFs, dirs...); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	for _, addon := range fixable {
+		if flags.dryRun {
+			yellow.Printf("Would have moved: %q to %q\n", addon.Dir, addon.Target)
+		} else {
+			fmt.Printf("Moving: %s to %s\n", addon.Dir, addon.Target)
+			if err := addon.Fix(AppFs); err != nil {
+				red.Printf("Error moving %s: %s\n", addon.Dir, err)
+			}
+		}
+	}
+}
+
+func init() {
+	CheckLayoutCmd.Flags().BoolVarP(&flags.backup, "backup", "", false, "Performs a backup prior to any destructive actions")
+	CheckLayoutCmd.Flags().BoolVarP(&flags.fix, "fix", "", false, "Moves mis-extracted AddOns into the correct layout")
+	CheckLayoutCmd.Flags().BoolVarP(&flags.dryRun, "dry-run", "", false, "Shows what changes would be made without actually making them. Use this to double-check before using --fix")
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	disable      []string
+	failuresOnly bool
+}
+
+var (
+	red    = pterm.NewStyle(pterm.FgRed)
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+	cyan   = pterm.NewStyle(pterm.FgCyan)
+	blue   = pterm.NewStyle(pterm.FgBlue)
+)
+
+// CheckLoadOrderCmd represents the loadorder command
+var CheckLoadOrderCmd = &cobra.Command{
+	Use:   "loadorder",
+	Short: "Simulates the order the game loads ESO AddOns in",
+	Long: `Simulates how the game resolves the installed AddOns, printing the predicted load order and every AddOn which won't load.
+
+An AddOn won't load if it's disabled, part of a circular dependency, or if a required dependency is missing,
+too old, or won't load itself (so a single missing library can stop a whole chain of AddOns from loading).
+AddOns load after their required and optional dependencies.
+
+Use --disable to see what would happen if some AddOns were turned off.
+Exits with status 1 if any AddOns (other than those disabled) won't load.`,
+	Example: `  esotools check loadorder --disable LibAddonMenu-2.0`,
+	Run:     execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	addons, errs := eso.Run()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		os.Exit(2)
+	}
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	graph := eso.NewDependencyGraph(addons)
+
+	for _, name := range flags.disable {
+		if _, exists := addons.Find(name); !exists {
+			yellow.Printf("Cannot disable %s, it is not installed\n", name)
+		}
+	}
+
+	plan := graph.LoadOrder(flags.disable...)
+
+	if !flags.failuresOnly {
+		blue.Println("Load order:")
+		for i, key := range plan.Order {
+			fmt.Printf("%5d. %s\n", i+1, key)
+		}
+		fmt.Println()
+	}
+
+	var failed int
+	for _, failure := range plan.Failures {
+		color := red
+		if failure.Reason == eso.LoadDisabled {
+			color = yellow
+		} else {
+			failed++
+		}
+
+		reason := failure.String()
+		if cause, exists := plan.Failure(failure.Cause); exists && failure.Cause != failure.Dependency {
+			reason += blue.Sprintf(" (%s %s)", cause.Key, cause)
+		}
+
+		fmt.Printf(
+			"%s won't load, %s\n",
+			color.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", failure.Key),
+			reason,
+		)
+	}
+
+	if len(plan.Failures) > 0 {
+		fmt.Println()
+	}
+
+	if failed == 0 {
+		green.Printf("%d %s will load\n", len(plan.Order), eso.Pluralize("AddOn", len(plan.Order)))
+		return
+	}
+
+	cyan.Printf(
+		"%d %s will load, %d %s won't\n",
+		len(plan.Order), eso.Pluralize("AddOn", len(plan.Order)),
+		failed, eso.Pluralize("AddOn", failed),
+	)
+
+	os.Exit(1)
+}
+
+func init() {
+	CheckLoadOrderCmd.Flags().StringSliceVarP(&flags.disable, "disable", "d", []string{}, "AddOns to treat as disabled (comma separated, or repeat the flag)")
+	CheckLoadOrderCmd.Flags().BoolVarP(&flags.failuresOnly, "failures-only", "F", false, "Only report AddOns which won't load, without the load order")
+}
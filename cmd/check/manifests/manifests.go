@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	red    = pterm.NewStyle(pterm.FgRed)
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+	cyan   = pterm.NewStyle(pterm.FgCyan)
+)
+
+var flags struct {
+	errorsOnly bool
+}
+
+// CheckManifestsCmd represents the manifests command
+var CheckManifestsCmd = &cobra.Command{
+	Use:   "manifests",
+	Short: "Lints the manifest files of installed ESO AddOns",
+	Long: `Checks every AddOn manifest in the ESO AddOns directory for mistakes that cause the game to silently ignore all or part of an AddOn.
+
+This includes files listed but missing on disk, UTF-8 BOM or UTF-16 encoding, mixed line endings, overly long lines,
+unknown or misspelled directives, a missing APIVersion, and malformed version constraints.
+
+Exits with status 1 if any errors are found.`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var numberOfErrors, numberOfWarnings int
+	var verbosity = viper.GetInt("verbosity")
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	definitions, err := eso.FindAddOns(eso.AppFs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	for _, definition := range definitions {
+		manifest, err := eso.ReadManifest(eso.AppFs, definition.Path())
+		if err != nil {
+			red.Printf("%s: %s\n", definition.Path(), err)
+			numberOfErrors++
+			continue
+		}
+
+		path := strings.TrimPrefix(manifest.Path, string(filepath.Separator))
+
+		if verbosity >= 2 {
+			cyan.Printf("Checking %s\n", path)
+		}
+
+		for _, issue := range manifest.Lint(eso.AppFs) {
+			if issue.Severity == eso.IssueError {
+				numberOfErrors++
+				fmt.Printf("%s: %s %s\n", cyan.Sprint(path), red.Sprint(issue.Severity), issue)
+			} else {
+				numberOfWarnings++
+				if !flags.errorsOnly {
+					fmt.Printf("%s: %s %s\n", cyan.Sprint(path), yellow.Sprint(issue.Severity), issue)
+				}
+			}
+		}
+	}
+
+	if numberOfErrors+numberOfWarnings > 0 {
+		fmt.Println()
+	}
+
+	summary := fmt.Sprintf(
+		"Checked %d %s: %d %s, %d %s\n",
+		len(definitions), eso.Pluralize("manifest", len(definitions)),
+		numberOfErrors, eso.Pluralize("error", numberOfErrors),
+		numberOfWarnings, eso.Pluralize("warning", numberOfWarnings),
+	)
+
+	switch {
+	case numberOfErrors > 0:
+		red.Print(summary)
+		os.Exit(1)
+	case numberOfWarnings > 0:
+		yellow.Print(summary)
+	default:
+		green.Print(summary)
+	}
+}
+
+func init() {
+	CheckManifestsCmd.Flags().BoolVarP(&flags.errorsOnly, "errors-only", "e", false, "Only report errors, suppressing warnings")
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	backupSavedVarsCmd "github.com/dyoung522/esotools/cmd/backup/saved_vars"
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	backup bool
+	clean  bool
+	dryRun bool
+}
+
+var (
+	red     = pterm.NewStyle(pterm.FgRed)
+	caution = pterm.NewStyle(pterm.BgRed, pterm.FgYellow, pterm.Bold)
+	yellow  = pterm.NewStyle(pterm.FgYellow)
+	green   = pterm.NewStyle(pterm.FgGreen)
+	cyan    = pterm.NewStyle(pterm.FgCyan)
+	blue    = pterm.NewStyle(pterm.FgBlue)
+)
+
+// CheckOrphansCmd represents the orphans command
+var CheckOrphansCmd = &cobra.Command{
+	Use:   "orphans",
+	Short: "Reports libraries which no installed AddOn uses",
+	Long: `Reports installed libraries (AddOns flagged with IsLibrary, or named like "LibSomething") which no other installed AddOn
+requires or optionally uses, along with their size on disk and their SavedVariables file.
+
+Libraries only used by other orphaned libraries are reported as well, as they'd be orphaned once those are removed.
+Optionally, you can remove them (and their SavedVariables) with the --clean flag.`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+	var totalSize int64
+
+	addons, errs := eso.Run()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		os.Exit(2)
+	}
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	orphans, err := addons.Orphans(AppFs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	if len(orphans) == 0 {
+		green.Println("No orphaned libraries found")
+		return
+	}
+
+	for _, orphan := range orphans {
+		var notes []string
+
+		if orphan.SavedVars != "" {
+			notes = append(notes, fmt.Sprintf("SavedVariables: %s (%s)", orphan.SavedVars, eso.FormatSize(orphan.SavedVarsSize)))
+		}
+
+		if len(orphan.UsedBy) > 0 {
+			notes = append(notes, fmt.Sprintf("only used by %s", strings.Join(orphan.UsedBy, ", ")))
+		}
+
+		fmt.Printf(
+			"%s %s %s\n",
+			yellow.Add(*pterm.Bold.ToStyle()).Sprintf("%-30s", orphan.Key),
+			cyan.Sprintf("%10s", eso.FormatSize(orphan.Size)),
+			blue.Sprint(strings.Join(notes, ", ")),
+		)
+
+		totalSize += orphan.Size + orphan.SavedVarsSize
+	}
+
+	fmt.Println()
+	yellow.Printf(
+		"Found %d orphaned %s using %s\n",
+		len(orphans), eso.Pluralize("library", len(orphans)), eso.FormatSize(totalSize),
+	)
+
+	if flags.clean {
+		clean(AppFs, orphans)
+	}
+}
+
+func clean(AppFs afero.Fs, orphans []eso.OrphanedAddOn) {
+	var removePrompt = "Remove the orphaned libraries and their SavedVariables?"
+	var dirs, savedVars []string
+
+	for _, orphan := range orphans {
+		dirs = append(dirs, orphan.Dirs...)
+
+		if orphan.SavedVars != "" {
+			savedVars = append(savedVars, orphan.SavedVars)
+		}
+	}
+
+	if flags.dryRun {
+		removePrompt += " [dry-run enabled, no destructive actions will be taken]"
+	}
+
+	if result, _ := pterm.DefaultInteractiveConfirm.Show(removePrompt); !result {
+		return
+	}
+
+	if !flags.backup && !flags.dryRun {
+		savePrompt := caution.Sprint("This opperation is destructive, do you want to make a backup first?")
+
+		if result, _ := pterm.DefaultInteractiveConfirm.Show(savePrompt); result {
+			flags.backup = true
+		}
+	}
+
+	if flags.backup {
+		if _, err := eso.BackupAddOns(AppFs, dirs...); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+
+		if len(savedVars) > 0 {
+			if err := backupSavedVarsCmd.BackupSavedVars(AppFs); err != nil {
+				fmt.Println(err)
+				os.Exit(2)
+			}
+		}
+	}
+
+	for _, dir := range dirs {
+		remove(AppFs, filepath.Join(eso.AddOnsPath(), dir))
+	}
+
+	for _, savedVar := range savedVars {
+		remove(AppFs, filepath.Join(eso.SavedVariablesPath(), savedVar))
+	}
+}
+
+func remove(AppFs afero.Fs, path string) {
+	if flags.dryRun {
+		yellow.Printf("Would have removed: %q\n", path)
+		return
+	}
+
+	fmt.Println("Removing:", path)
+	if err := AppFs.RemoveAll(path); err != nil {
+		red.Printf("Error removing %s: %s\n", path, err)
+	}
+}
+
+func init() {
+	CheckOrphansCmd.Flags().BoolVarP(&flags.backup, "backup", "", false, "Performs a backup prior to any destructive actions")
+	CheckOrphansCmd.Flags().BoolVarP(&flags.clean, "clean", "", false, "Removes the orphaned libraries and their SavedVariables files")
+	CheckOrphansCmd.Flags().BoolVarP(&flags.dryRun, "dry-run", "", false, "Shows what changes would be made without actually making them. Use this to double-check before using --clean")
+}
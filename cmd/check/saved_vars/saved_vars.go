@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	backupCmd "github.com/dyoung522/esotools/cmd/backup/saved_vars"
@@ -14,9 +15,10 @@ import (
 )
 
 var flags struct {
-	backup bool
-	clean  bool
-	dryRun bool
+	backup    bool
+	clean     bool
+	dryRun    bool
+	integrity bool
 }
 
 var (
@@ -32,14 +34,27 @@ var (
 var CheckSavedVarsCmd = &cobra.Command{
 	Use:   "savedvars",
 	Short: "Checks validity of ESO SavedVariables files",
-	Long:  "Specifically, it reports on extraneous SavedVariable files that do not correspond to any known AddOn.\nOptionally, you can auto-remove them with the --clean flag.",
-	Run:   execute,
+	Long: `Specifically, it reports on extraneous SavedVariable files that do not correspond to any known AddOn.
+Optionally, you can auto-remove them with the --clean flag.
+
+Files are matched by the variables declared within them, against the variables each AddOn declares in its manifest
+(## SavedVariables: Name), as well as by name. Variables which no installed AddOn declares are also reported, even
+within files which are kept.
+
+With --integrity, every file is also parsed to find those which are empty or corrupt (reporting the line and column of
+the syntax error), or which have shrunk to less than half of their last good copy within the saved_variables_*.zip
+backups in the current directory (see "esotools backup savedvars"). A crash while the game is saving can leave a
+file truncated, which AddOns then silently reset; damaged files may be restored from the last good backup. As pruning or
+deleting SavedVariables shrinks files too, each shrunk file is only restored once confirmed on its own.`,
+	Run: execute,
 }
 
 func execute(cmd *cobra.Command, args []string) {
 	var verbosity = viper.GetInt("verbosity")
 	var AppFs = afero.NewOsFs()
 	var extraneousSavedVars []eso.SavedVars
+	var unclaimedSavedVars []eso.SavedVarsClaim
+	var removed = make(map[string]bool)
 
 	addons, errs := eso.Run()
 	if len(errs) > 0 {
@@ -63,12 +78,24 @@ func execute(cmd *cobra.Command, args []string) {
 		savedVarKey := strings.TrimSuffix(savedVar.FileInfo.Name(), ".lua")
 
 		// Skip Zenemax Online files
-		if strings.HasPrefix(savedVarKey, "ZO_") {
+		if strings.HasPrefix(savedVarKey, "ZO_") || savedVar.IsDir() {
 			continue
 		}
 
-		if _, err := addons.Find(savedVarKey); !err {
+		var variables []string
+
+		if document, err := savedVar.Parse(AppFs); err == nil {
+			variables = document.Names()
+		} else if verbosity >= 1 {
+			fmt.Fprint(os.Stderr, yellow.Sprintf("Matching %s by name only, as its variables couldn't be read: %s\n", savedVar.Name(), err))
+		}
+
+		claim := addons.ClaimSavedVars(savedVar.Name(), variables)
+
+		if !claim.Claimed() {
 			extraneousSavedVars = append(extraneousSavedVars, savedVar)
+		} else if len(claim.Unclaimed) > 0 {
+			unclaimedSavedVars = append(unclaimedSavedVars, claim)
 		}
 	}
 
@@ -113,6 +140,8 @@ func execute(cmd *cobra.Command, args []string) {
 						fmt.Println("Removing:", savedVar.FullPath())
 						if err := AppFs.Remove(savedVar.FullPath()); err != nil {
 							red.Printf("Error removing %s: %s\n", savedVar.FileInfo.Name(), err)
+						} else {
+							removed[savedVar.FullPath()] = true
 						}
 					}
 				}
@@ -121,10 +150,193 @@ func execute(cmd *cobra.Command, args []string) {
 	} else {
 		green.Println("No extraneous SavedVariables found")
 	}
+
+	reportUnclaimed(unclaimedSavedVars)
+
+	if flags.integrity {
+		var remainingSavedVars []eso.SavedVars
+
+		// Files removed by --clean are gone, so there's nothing left to check
+		for _, savedVar := range savedVarFiles {
+			if !removed[savedVar.FullPath()] {
+				remainingSavedVars = append(remainingSavedVars, savedVar)
+			}
+		}
+
+		checkIntegrity(AppFs, remainingSavedVars)
+	}
+}
+
+// Reports the variables which no installed AddOn declares, within files which are otherwise claimed
+func reportUnclaimed(claims []eso.SavedVarsClaim) {
+	var numberOfUnclaimed = 0
+
+	for _, claim := range claims {
+		numberOfUnclaimed += len(claim.Unclaimed)
+	}
+
+	if numberOfUnclaimed == 0 {
+		return
+	}
+
+	yellow.Printf(
+		"Found %d unclaimed SavedVariables %s in %d %s\n",
+		numberOfUnclaimed, eso.Pluralize("variable", numberOfUnclaimed), len(claims), eso.Pluralize("file", len(claims)),
+	)
+
+	for _, claim := range claims {
+		fmt.Printf("- %s (%s): %s\n", cyan.Sprint(claim.File), strings.Join(claim.Claimants(), ", "), strings.Join(claim.Unclaimed, ", "))
+	}
+}
+
+// Reports files which are empty, corrupt, or have shrunk since the last backup, and offers to restore them from the last
+// good backup
+func checkIntegrity(AppFs afero.Fs, savedVarFiles []eso.SavedVars) {
+	var damaged []eso.SavedVarsIntegrity
+	var restorable []eso.SavedVarsIntegrity
+
+	backups, err := eso.FindSavedVarsBackups(AppFs, ".")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	for _, savedVar := range savedVarFiles {
+		if savedVar.IsDir() || !strings.HasSuffix(strings.ToLower(savedVar.Name()), ".lua") {
+			continue
+		}
+
+		integrity, err := eso.CheckSavedVarsIntegrity(AppFs, savedVar.FullPath(), backups)
+		if err != nil {
+			red.Println(err)
+			continue
+		}
+
+		if integrity.Problem != eso.SavedVarsIntact {
+			damaged = append(damaged, integrity)
+		}
+	}
+
+	if len(damaged) == 0 {
+		green.Println("No damaged SavedVariables found")
+		return
+	}
+
+	yellow.Printf("Found %d damaged SavedVariable %s\n", len(damaged), eso.Pluralize("file", len(damaged)))
+
+	for _, integrity := range damaged {
+		var problem string
+
+		switch integrity.Problem {
+		case eso.SavedVarsCorrupt:
+			problem = fmt.Sprintf("corrupt at %s", integrity.Err)
+		case eso.SavedVarsShrunk:
+			problem = fmt.Sprintf("shrunk from %s to %s", eso.FormatSize(int64(len(integrity.BackupData))), eso.FormatSize(integrity.Size))
+		default:
+			problem = integrity.Problem.String()
+		}
+
+		if integrity.Restorable() {
+			restorable = append(restorable, integrity)
+		}
+
+		switch {
+		case integrity.Backup == "":
+			problem += pterm.Gray(" (no good copy in any backup)")
+		case integrity.MayBeDeliberate():
+			problem += pterm.Gray(fmt.Sprintf(" (compare with \"esotools savedvars diff --backup %s %s\")", integrity.Backup, filepath.Base(integrity.Path)))
+		default:
+			problem += pterm.Gray(fmt.Sprintf(" (last good copy in %s)", integrity.Backup))
+		}
+
+		fmt.Printf("- %s: %s\n", cyan.Sprint(filepath.Base(integrity.Path)), problem)
+	}
+
+	if len(restorable) == 0 {
+		if len(backups) == 0 {
+			fmt.Println("No SavedVariables backups (saved_variables_*.zip) found in the current directory to restore from")
+		}
+
+		return
+	}
+
+	var confirmed []eso.SavedVarsIntegrity
+	var broken []eso.SavedVarsIntegrity
+	var dryRunNote string
+
+	if flags.dryRun {
+		dryRunNote = " [dry-run enabled, no destructive actions will be taken]"
+	}
+
+	for _, integrity := range restorable {
+		if !integrity.MayBeDeliberate() {
+			broken = append(broken, integrity)
+		}
+	}
+
+	if len(broken) > 0 {
+		restorePrompt := fmt.Sprintf("Restore %d empty or corrupt %s from the last good backup?", len(broken), eso.Pluralize("file", len(broken)))
+
+		if result, _ := pterm.DefaultInteractiveConfirm.Show(restorePrompt + dryRunNote); result {
+			confirmed = append(confirmed, broken...)
+		}
+	}
+
+	// Shrunk files may have been pruned on purpose, so each one is confirmed on its own
+	for _, integrity := range restorable {
+		if !integrity.MayBeDeliberate() {
+			continue
+		}
+
+		restorePrompt := caution.Sprintf("%s may have been pruned or edited on purpose, and restoring it would bring back what was removed.", filepath.Base(integrity.Path)) +
+			fmt.Sprintf(" Restore it from %s anyway?", integrity.Backup)
+
+		if result, _ := pterm.DefaultInteractiveConfirm.Show(restorePrompt + dryRunNote); result {
+			confirmed = append(confirmed, integrity)
+		}
+	}
+
+	if len(confirmed) == 0 {
+		return
+	}
+
+	if !flags.backup && !flags.dryRun {
+		savePrompt := caution.Sprint("This opperation is destructive, do you want to make a backup first?")
+
+		if result, _ := pterm.DefaultInteractiveConfirm.Show(savePrompt); result {
+			flags.backup = true
+		}
+	}
+
+	if flags.backup && !flags.dryRun {
+		var names []string
+
+		for _, integrity := range confirmed {
+			names = append(names, filepath.Base(integrity.Path))
+		}
+
+		if err := backupCmd.BackupSavedVars(AppFs, names...); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	for _, integrity := range confirmed {
+		if flags.dryRun {
+			yellow.Printf("Would have restored: %q from %q\n", integrity.Path, integrity.Backup)
+			continue
+		}
+
+		fmt.Println("Restoring:", integrity.Path)
+		if err := eso.WriteFileAtomic(AppFs, integrity.Path, integrity.BackupData); err != nil {
+			red.Printf("Error restoring %s: %s\n", filepath.Base(integrity.Path), err)
+		}
+	}
 }
 
 func init() {
 	CheckSavedVarsCmd.Flags().BoolVarP(&flags.backup, "backup", "", false, "Performs a backup prior to any destructive actions")
 	CheckSavedVarsCmd.Flags().BoolVarP(&flags.clean, "clean", "", false, "Removes extranious SavedVariable files")
+	CheckSavedVarsCmd.Flags().BoolVarP(&flags.integrity, "integrity", "", false, "Also reports empty, corrupt, or shrunk SavedVariable files, and offers to restore them from the last good backup")
 	CheckSavedVarsCmd.Flags().BoolVarP(&flags.dryRun, "dry-run", "", false, "Shows what changes would be made without actually making them. Use this to double-check before using --clean")
 }
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/cobra"
+)
+
+var flags struct {
+	format     string
+	noOptional bool
+}
+
+// GraphCmd represents the graph command
+var GraphCmd = &cobra.Command{
+	Use:   "graph [addon...]",
+	Short: "Exports the dependency graph of installed ESO AddOns",
+	Long: `Exports a graph of installed AddOns and the AddOns they depend upon, in Graphviz DOT, Mermaid, GraphML, or JSON format.
+
+Optional dependencies are drawn dashed, and dependencies which aren't installed are highlighted.
+If any AddOns are given, only the part of the graph reachable from them is exported.`,
+	Example: `  esotools graph | dot -Tsvg -o addons.svg
+  esotools graph --format mermaid MyAddon`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	addons, errs := eso.Run()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		os.Exit(2)
+	}
+
+	graph := eso.NewDependencyGraph(addons)
+
+	if len(args) > 0 {
+		subgraph, err := graph.Subgraph(!flags.noOptional, args...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		graph = subgraph
+	} else if flags.noOptional {
+		graph = graph.WithoutOptional()
+	}
+
+	switch flags.format {
+	case "dot":
+		fmt.Print(graph.ToDOT())
+	case "mermaid":
+		fmt.Print(graph.ToMermaid())
+	case "graphml":
+		fmt.Print(graph.ToGraphML())
+	case "json":
+		output, err := graph.ToJson()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+
+		fmt.Println(string(output))
+	default:
+		fmt.Printf("unknown format %q, expected one of dot, mermaid, graphml, or json\n", flags.format)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	GraphCmd.Flags().StringVarP(&flags.format, "format", "f", "dot", "Output format: dot, mermaid, graphml, or json")
+	GraphCmd.Flags().BoolVarP(&flags.noOptional, "no-optional", "O", false, "Only include required dependencies")
+}
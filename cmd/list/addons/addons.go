@@ -5,27 +5,45 @@ import (
 	"os"
 
 	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+var (
+	red    = pterm.NewStyle(pterm.FgRed)
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+	cyan   = pterm.NewStyle(pterm.FgCyan)
+	blue   = pterm.NewStyle(pterm.FgBlue)
+)
+
 var flags struct {
 	simple   bool
 	markdown bool
 	json     bool
 	raw      bool
+	tree     bool
+	depsTree bool
+	closure  bool
 	noDeps   bool
 	noLibs   bool
 }
 
 // ListAddOnsCmd represents the addons command
 var ListAddOnsCmd = &cobra.Command{
-	Use:   "addons",
+	Use:   "addons [addon...]",
 	Short: "Lists installed ESO AddOns",
 	Long: `Lists AddOns installed in the ESO AddOns directory.
 
 By default, this will print out a simple list with only one AddOn per line. However, other formats may be specified via the flags.
+
+The --deps-tree and --closure flags only consider the given AddOns (or every AddOn nothing else depends upon, if none are given).
+--deps-tree prints the full tree of required and optional dependencies of each, while --closure prints the minimum set of
+folders needed to run them, which is what you'd need to copy to share your setup.
 `,
+	Example: `  esotools list addons --deps-tree MyAddon
+  esotools list addons --closure MyAddon OtherAddon`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		addons, errs := eso.Run()
@@ -37,7 +55,18 @@ By default, this will print out a simple list with only one AddOn per line. Howe
 			os.Exit(1)
 		}
 
+		if len(args) > 0 && !flags.depsTree && !flags.closure {
+			fmt.Println("AddOn names may only be given with --deps-tree or --closure")
+			os.Exit(1)
+		}
+
 		switch {
+		case flags.tree:
+			printTree(addons)
+		case flags.depsTree:
+			printDependencyTree(addons, args)
+		case flags.closure:
+			printClosure(addons, args)
 		case flags.json:
 			fmt.Println(addons.Print("json"))
 		case flags.markdown:
@@ -50,6 +79,162 @@ By default, this will print out a simple list with only one AddOn per line. Howe
 	},
 }
 
+// Prints the AddOns as a tree, nesting embedded AddOns under the AddOn they're bundled with,
+// followed by which copy of each duplicated AddOn the game will load
+func printTree(addons eso.AddOns) {
+	var root = pterm.TreeNode{Text: eso.AddOnsPath()}
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	for _, node := range addons.Tree() {
+		root.Children = append(root.Children, treeNode(node))
+	}
+
+	_ = pterm.DefaultTree.WithRoot(root).Render()
+
+	for _, key := range addons.Keys() {
+		addon := addons[key]
+
+		if len(addon.Copies()) == 0 {
+			continue
+		}
+
+		pterm.Printf(
+			"%s: loading %s from %s\n",
+			cyan.Sprint(key),
+			green.Sprintf("AddOnVersion %s", versionString(addon)),
+			addon.Dir(),
+		)
+
+		for _, copy := range addon.Copies() {
+			pterm.Printf("  ignoring %s from %s\n", yellow.Sprintf("AddOnVersion %s", versionString(copy)), copy.Dir())
+		}
+	}
+}
+
+// Prints the full transitive dependency tree of each AddOn
+func printDependencyTree(addons eso.AddOns, names []string) {
+	var root = pterm.TreeNode{Text: eso.AddOnsPath()}
+	var graph = eso.NewDependencyGraph(addons)
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	if len(names) == 0 {
+		names = graph.TopLevel()
+	}
+
+	trees, err := graph.DependencyTree(true, names...)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	for _, tree := range trees {
+		root.Children = append(root.Children, dependencyTreeNode(tree))
+	}
+
+	_ = pterm.DefaultTree.WithRoot(root).Render()
+}
+
+func dependencyTreeNode(node *eso.DependencyTreeNode) pterm.TreeNode {
+	var text = node.Node.Key
+
+	if edge := node.Edge; edge != nil {
+		if edge.Constraint != "" {
+			text += " " + cyan.Sprint(edge.Constraint)
+		}
+
+		if edge.Type == eso.OptionalEdge {
+			text += " " + blue.Sprint("(optional)")
+		}
+
+		if edge.Outdated {
+			installed := node.Node.AddOnVersion
+			if installed == "" {
+				installed = "(none)"
+			}
+
+			text += " " + red.Sprintf("[outdated, AddOnVersion %s installed]", installed)
+		}
+	}
+
+	switch {
+	case node.Node.Missing:
+		text += " " + red.Sprint("[missing]")
+	case node.Shared:
+		text += " " + green.Sprint("[shared]")
+	}
+
+	if node.Circular {
+		text += " " + yellow.Sprint("[circular]")
+	}
+
+	var tree = pterm.TreeNode{Text: text}
+
+	for _, child := range node.Children {
+		tree.Children = append(tree.Children, dependencyTreeNode(child))
+	}
+
+	return tree
+}
+
+// Prints the minimum set of folders needed to run the AddOns, one per line
+func printClosure(addons eso.AddOns, names []string) {
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	if len(names) == 0 {
+		names = eso.NewDependencyGraph(addons).TopLevel()
+	}
+
+	folders, missing, err := addons.Closure(names...)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	for _, folder := range folders {
+		fmt.Println(folder)
+	}
+
+	for _, key := range missing {
+		fmt.Fprint(os.Stderr, yellow.Sprintf("%s is required, but not installed\n", key))
+	}
+}
+
+func treeNode(node *eso.AddOnNode) pterm.TreeNode {
+	var text = node.AddOn.TitleString()
+
+	if node.AddOn.Parent() != "" {
+		text += " " + blue.Sprint(node.AddOn.Dir())
+	}
+
+	if !node.Loaded {
+		text += " " + yellow.Sprint("[not loaded, a newer copy is installed]")
+	}
+
+	var tree = pterm.TreeNode{Text: text}
+
+	for _, child := range node.Children {
+		tree.Children = append(tree.Children, treeNode(child))
+	}
+
+	return tree
+}
+
+func versionString(addon eso.AddOn) string {
+	if addon.AddOnVersion == "" {
+		return "(none)"
+	}
+
+	return addon.AddOnVersion
+}
+
 func init() {
 	var err error
 
@@ -57,7 +242,10 @@ func init() {
 	ListAddOnsCmd.Flags().BoolVarP(&flags.markdown, "markdown", "m", false, "Print out the list in markdown format")
 	ListAddOnsCmd.Flags().BoolVarP(&flags.raw, "raw", "r", false, "Print out the list in the RAW ESO AddOn header format (most verbose)")
 	ListAddOnsCmd.Flags().BoolVarP(&flags.simple, "simple", "s", false, "Prints the AddOn listing in simple plain text")
-	ListAddOnsCmd.MarkFlagsMutuallyExclusive("json", "markdown", "raw", "simple")
+	ListAddOnsCmd.Flags().BoolVarP(&flags.tree, "tree", "t", false, "Prints every installed copy of each AddOn, showing which AddOns are embedded within others")
+	ListAddOnsCmd.Flags().BoolVarP(&flags.depsTree, "deps-tree", "", false, "Prints the full tree of required and optional dependencies of the given AddOns")
+	ListAddOnsCmd.Flags().BoolVarP(&flags.closure, "closure", "", false, "Prints the minimum set of folders needed to run the given AddOns")
+	ListAddOnsCmd.MarkFlagsMutuallyExclusive("json", "markdown", "raw", "simple", "tree", "deps-tree", "closure")
 
 	ListAddOnsCmd.Flags().BoolVarP(&flags.noLibs, "no-libs", "L", false, "Suppresses printing of AddOns that are considered Libraries")
 	err = viper.BindPFlag("noLibs", ListAddOnsCmd.Flags().Lookup("no-libs"))
@@ -9,7 +9,10 @@ import (
 
 	sub3 "github.com/dyoung522/esotools/cmd/backup"
 	sub2 "github.com/dyoung522/esotools/cmd/check"
+	sub4 "github.com/dyoung522/esotools/cmd/graph"
 	sub1 "github.com/dyoung522/esotools/cmd/list"
+	sub6 "github.com/dyoung522/esotools/cmd/savedvars"
+	sub5 "github.com/dyoung522/esotools/cmd/why"
 	"github.com/dyoung522/esotools/lib/eso"
 	cc "github.com/ivanpirog/coloredcobra"
 	"github.com/spf13/cobra"
@@ -77,6 +80,9 @@ func init() {
 	RootCmd.AddCommand(sub1.ListCmd)
 	RootCmd.AddCommand(sub2.CheckCmd)
 	RootCmd.AddCommand(sub3.BackupCmd)
+	RootCmd.AddCommand(sub4.GraphCmd)
+	RootCmd.AddCommand(sub5.WhyCmd)
+	RootCmd.AddCommand(sub6.SavedVarsCmd)
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	output string
+}
+
+var (
+	red    = pterm.NewStyle(pterm.FgRed)
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+	cyan   = pterm.NewStyle(pterm.FgCyan)
+	blue   = pterm.NewStyle(pterm.FgBlue)
+)
+
+// An anonymized SavedVariables file
+type savedVarsFile struct {
+	eso.SavedVarsFile
+	data []byte
+}
+
+// SavedVarsAnonymizeCmd represents the savedvars anonymize command
+var SavedVarsAnonymizeCmd = &cobra.Command{
+	Use:   "anonymize [file|addon...]",
+	Short: "Anonymizes SavedVariables files for sharing, such as with AddOn authors",
+	Long: `Writes a copy of each SavedVariables file (or only those given) with account names, character names, and character ids
+replaced by pseudonyms, such as "@Account1" and "Character1", for attaching to bug reports. The original files are left alone.
+
+Each name is given the same pseudonym in every file, as a whole key or value, or as a whole word within other text.
+The values of other fields, such as guild names, may be replaced too with --field (or "anonymize_fields" in the config file).
+
+The copies are written to a new ZIP file, or to a single .lua file with --output. Use --verbose to see which pseudonym
+each name was given. Free-form text, such as notes, may still contain other personal details, so check the copies
+before sharing them.`,
+	Example: `  esotools savedvars anonymize MyAddon
+  esotools savedvars anonymize --field guildName --field guildNote MyAddon OtherAddon
+  esotools savedvars anonymize --output MyAddon.lua MyAddon`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var verbosity = viper.GetInt("verbosity")
+	var AppFs = afero.NewOsFs()
+	var anonymizer = eso.NewAnonymizer(viper.GetStringSlice("anonymize_fields")...)
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	savedVarFiles, skipped, err := eso.ReadSavedVarsFiles(AppFs, args...)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	for _, err := range skipped {
+		fmt.Fprint(os.Stderr, yellow.Sprintf("Skipping %s\n", err))
+	}
+
+	var files []savedVarsFile
+
+	for _, file := range savedVarFiles {
+		files = append(files, savedVarsFile{SavedVarsFile: file})
+	}
+
+	if len(files) == 0 {
+		yellow.Println("No SavedVariables files found")
+		return
+	}
+
+	var output = flags.output
+	var asZip = output == "" || strings.EqualFold(filepath.Ext(output), ".zip")
+
+	if output == "" {
+		t := time.Now()
+		output = fmt.Sprintf("anonymized_saved_variables_%d%02d%02d%02d%02d%02d.zip", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+	}
+
+	if !asZip && len(files) > 1 {
+		fmt.Println("--output must be a .zip file when anonymizing more than one file")
+		os.Exit(1)
+	}
+
+	if exists, _ := afero.Exists(AppFs, output); exists {
+		fmt.Printf("%q already exists, choose another --output\n", output)
+		os.Exit(1)
+	}
+
+	// Names are learned from every file, as any file may mention the accounts and characters found within another
+	for _, file := range files {
+		anonymizer.Learn(file.Document)
+	}
+
+	if len(args) > 0 {
+		skipped, err := learnOthers(AppFs, anonymizer, files)
+		if err != nil {
+			fmt.Fprint(os.Stderr, yellow.Sprintf("Only learning names from the given files, %s\n", err))
+		}
+
+		for _, err := range skipped {
+			fmt.Fprint(os.Stderr, yellow.Sprintf("Not learning names from %s\n", err))
+		}
+	}
+
+	for i := range files {
+		if files[i].data, err = anonymizer.Anonymize(files[i].Document); err != nil {
+			red.Printf("Error anonymizing %s: %s\n", files[i].Name(), err)
+			os.Exit(2)
+		}
+	}
+
+	if asZip {
+		err = writeZip(AppFs, output, files)
+	} else {
+		err = afero.WriteFile(AppFs, output, files[0].data, 0644)
+	}
+
+	if err != nil {
+		red.Printf("Error writing %s: %s\n", output, err)
+		os.Exit(2)
+	}
+
+	var names = anonymizer.Names()
+
+	for _, file := range files {
+		fmt.Println("Anonymized:", cyan.Sprint(file.Name()))
+	}
+
+	if verbosity >= 1 {
+		for _, name := range names {
+			fmt.Printf("  %s %s -> %s\n", pterm.Gray(fmt.Sprintf("%-14s", name.Kind)), name.Original, blue.Sprint(name.Pseudonym))
+		}
+	}
+
+	green.Printf("Replaced %d %s, written to %s\n", len(names), eso.Pluralize("name", len(names)), output)
+}
+
+// Writes the anonymized files to a new ZIP file
+func writeZip(AppFs afero.Fs, output string, files []savedVarsFile) error {
+	archiveFile, err := AppFs.Create(output)
+	if err != nil {
+		return err
+	}
+
+	defer archiveFile.Close()
+
+	zipWriter := zip.NewWriter(archiveFile)
+
+	for _, file := range files {
+		zipFile, err := zipWriter.Create(file.Name())
+		if err != nil {
+			return err
+		}
+
+		if _, err = zipFile.Write(file.data); err != nil {
+			return err
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+// Learns the names within the other SavedVariables files, which weren't given. Returns the errors of the files which
+// couldn't be read or parsed, and err if the SavedVariables directory can't be found.
+func learnOthers(AppFs afero.Fs, anonymizer *eso.Anonymizer, files []savedVarsFile) (skipped []error, err error) {
+	var given = make(map[string]bool)
+	var others []string
+
+	for _, file := range files {
+		given[absPath(file.Path)] = true
+	}
+
+	savedVarFiles, err := eso.FindSavedVars(AppFs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, savedVars := range savedVarFiles {
+		if !savedVars.IsDir() && strings.EqualFold(filepath.Ext(savedVars.Name()), ".lua") && !given[absPath(savedVars.FullPath())] {
+			others = append(others, savedVars.FullPath())
+		}
+	}
+
+	if len(others) == 0 {
+		return nil, nil
+	}
+
+	otherFiles, skipped, err := eso.ReadSavedVarsFiles(AppFs, others...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range otherFiles {
+		anonymizer.Learn(file.Document)
+	}
+
+	return skipped, nil
+}
+
+// Returns the absolute form of path, or path itself if it can't be determined
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+
+	return path
+}
+
+func init() {
+	SavedVarsAnonymizeCmd.Flags().StringVarP(&flags.output, "output", "o", "", "The .zip or .lua file to write (defaults to anonymized_saved_variables_<time>.zip in the current directory)")
+	SavedVarsAnonymizeCmd.Flags().StringSliceP("field", "f", []string{}, "Also replaces the values of fields with the given key, such as guildName (may be given more than once)")
+	err := viper.BindPFlag("anonymize_fields", SavedVarsAnonymizeCmd.Flags().Lookup("field"))
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	backupCmd "github.com/dyoung522/esotools/cmd/backup/saved_vars"
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	from   string
+	to     []string
+	files  []string
+	backup bool
+	dryRun bool
+}
+
+var (
+	red     = pterm.NewStyle(pterm.FgRed)
+	caution = pterm.NewStyle(pterm.BgRed, pterm.FgYellow, pterm.Bold)
+	yellow  = pterm.NewStyle(pterm.FgYellow)
+	green   = pterm.NewStyle(pterm.FgGreen)
+	cyan    = pterm.NewStyle(pterm.FgCyan)
+	blue    = pterm.NewStyle(pterm.FgBlue)
+)
+
+// A SavedVariables file and the result of copying settings within it
+type savedVarsFile struct {
+	eso.SavedVarsFile
+	data   []byte
+	copies []eso.SavedVarsCopy
+}
+
+// SavedVarsCopyCmd represents the savedvars copy command
+var SavedVarsCopyCmd = &cobra.Command{
+	Use:   "copy --from <@account/character> --to <@account/character>",
+	Short: "Copies AddOn settings from one character to others",
+	Long: `Copies the AddOn settings of one character to other characters, in every SavedVariables file (or only those given with --file).
+
+Characters are given as "@Account/Character", by name or id; the account may be left off if the character name is unique.
+Use "$AccountWide" as the character to copy to or from the account-wide settings, converting between
+character-specific and account-wide settings. The account of --to defaults to the account of --from.
+
+A character's settings can only be copied to characters which have logged in at least once with some AddOn installed,
+so that their ids are known. Everything else within each file is left exactly as it was.`,
+	Example: `  esotools savedvars copy --from "@Acct/Char A" --to "@Acct/Char B"
+  esotools savedvars copy --from "Char A" --to "Char B" --to "Char C" --file MyAddon --dry-run
+  esotools savedvars copy --from "@Acct/Char A" --to '@Acct/$AccountWide'`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	if flags.from == "" || len(flags.to) == 0 {
+		fmt.Println("both --from and --to are required")
+		os.Exit(1)
+	}
+
+	savedVarFiles, skipped, err := eso.ReadSavedVarsFiles(AppFs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	for _, err := range skipped {
+		fmt.Fprint(os.Stderr, yellow.Sprintf("Skipping %s\n", err))
+	}
+
+	selectedFiles, err := selectFiles(AppFs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var characters []eso.SavedVarsCharacter
+	var selected []eso.SavedVarsFile
+
+	for _, savedVars := range savedVarFiles {
+		// Characters are found in every file, so that their ids are known even if they have no settings to replace
+		characters = append(characters, savedVars.Document.Characters()...)
+
+		if selectedFiles == nil || selectedFiles[strings.ToLower(savedVars.Name())] {
+			selected = append(selected, savedVars)
+		}
+	}
+
+	from, err := resolve(characters, flags.from, "")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var to []eso.SavedVarsCharacter
+	var seen = make(map[string]bool)
+
+	for _, spec := range flags.to {
+		target, err := resolve(characters, spec, from.Account)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if !seen[target.ID()] {
+			seen[target.ID()] = true
+			to = append(to, target)
+		}
+	}
+
+	var files []savedVarsFile
+
+	for _, savedVars := range selected {
+		data, copies, err := savedVars.Document.CopySettings(from, to...)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if len(copies) > 0 {
+			files = append(files, savedVarsFile{SavedVarsFile: savedVars, data: data, copies: copies})
+		}
+	}
+
+	if len(files) == 0 {
+		yellow.Printf("No settings found for %s\n", describe(from))
+		return
+	}
+
+	for _, file := range files {
+		fmt.Println(cyan.Sprint(file.Name()))
+
+		for _, result := range file.copies {
+			var action = green.Sprint("add")
+			if result.Replaced {
+				action = yellow.Sprint("replace")
+			}
+
+			fmt.Printf("  %s %s\n", action, blue.Sprint(eso.FormatLuaPath(result.To)))
+		}
+	}
+
+	fmt.Println()
+
+	copySettings(AppFs, files, from, to)
+}
+
+func copySettings(AppFs afero.Fs, files []savedVarsFile, from eso.SavedVarsCharacter, to []eso.SavedVarsCharacter) {
+	var targets []string
+
+	for _, target := range to {
+		targets = append(targets, describe(target))
+	}
+
+	var copyPrompt = fmt.Sprintf(
+		"Copy the settings of %s to %s in %d %s?",
+		describe(from), strings.Join(targets, ", "), len(files), eso.Pluralize("file", len(files)),
+	)
+
+	if flags.dryRun {
+		copyPrompt += " [dry-run enabled, no destructive actions will be taken]"
+	}
+
+	if result, _ := pterm.DefaultInteractiveConfirm.Show(copyPrompt); !result {
+		return
+	}
+
+	if !flags.backup && !flags.dryRun {
+		savePrompt := caution.Sprint("This opperation is destructive, do you want to make a backup first?")
+
+		if result, _ := pterm.DefaultInteractiveConfirm.Show(savePrompt); result {
+			flags.backup = true
+		}
+	}
+
+	if flags.backup && !flags.dryRun {
+		if err := backupCmd.BackupSavedVars(AppFs); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	for _, file := range files {
+		if flags.dryRun {
+			yellow.Printf("Would have updated: %q\n", file.Path)
+			continue
+		}
+
+		fmt.Println("Updating:", file.Path)
+		if err := eso.WriteFileAtomic(AppFs, file.Path, file.data); err != nil {
+			red.Printf("Error updating %s: %s\n", file.Name(), err)
+		}
+	}
+}
+
+// Returns the character (or account-wide settings) given as "@Account/Character", by name or id
+func resolve(characters []eso.SavedVarsCharacter, spec string, defaultAccount string) (eso.SavedVarsCharacter, error) {
+	var matches = make(map[string]eso.SavedVarsCharacter)
+	var ids []string
+
+	account, name := eso.ParseSavedVarsOwner(spec)
+	if account == "" {
+		account = defaultAccount
+	}
+
+	if name == "" {
+		return eso.SavedVarsCharacter{}, fmt.Errorf("%q does not include a character, use \"@Account/Character\" or \"@Account/%s\"", spec, eso.AccountWide)
+	}
+
+	for _, character := range characters {
+		if account != "" && !strings.EqualFold(character.Account, account) {
+			continue
+		}
+
+		if strings.EqualFold(name, eso.AccountWide) {
+			character = eso.AccountWideOwner(character.Account)
+		} else if !character.Matches(name) {
+			continue
+		}
+
+		if _, ok := matches[character.ID()]; !ok {
+			matches[character.ID()] = character
+			ids = append(ids, character.ID())
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		if account == "" {
+			return eso.SavedVarsCharacter{}, fmt.Errorf("no SavedVariables found for %q, they must have logged in at least once", spec)
+		}
+
+		if strings.EqualFold(name, eso.AccountWide) {
+			return eso.AccountWideOwner(account), nil
+		}
+
+		return eso.SavedVarsCharacter{}, fmt.Errorf("no SavedVariables found for %q on %s, they must have logged in at least once", name, account)
+	case 1:
+		return matches[ids[0]], nil
+	default:
+		var found []string
+		for _, id := range ids {
+			found = append(found, describe(matches[id]))
+		}
+
+		return eso.SavedVarsCharacter{}, fmt.Errorf("%q is ambiguous, it could be any of: %s", spec, strings.Join(found, ", "))
+	}
+}
+
+// Returns the names of the files given with --file, in lower case, or nil if every file should be used
+func selectFiles(AppFs afero.Fs) (map[string]bool, error) {
+	if len(flags.files) == 0 {
+		return nil, nil
+	}
+
+	var selected = make(map[string]bool)
+
+	for _, name := range flags.files {
+		path, err := eso.FindSavedVarsFile(AppFs, name)
+		if err != nil {
+			return nil, err
+		}
+
+		selected[strings.ToLower(filepath.Base(path))] = true
+	}
+
+	return selected, nil
+}
+
+func describe(character eso.SavedVarsCharacter) string {
+	return character.Account + "/" + character.String()
+}
+
+func init() {
+	SavedVarsCopyCmd.Flags().StringVarP(&flags.from, "from", "", "", "The character to copy settings from, as \"@Account/Character\"")
+	SavedVarsCopyCmd.Flags().StringSliceVarP(&flags.to, "to", "", []string{}, "The character to copy settings to, as \"@Account/Character\" (may be given more than once)")
+	SavedVarsCopyCmd.Flags().StringSliceVarP(&flags.files, "file", "f", []string{}, "Only copy settings within the given SavedVariables file (may be given more than once)")
+	SavedVarsCopyCmd.Flags().BoolVarP(&flags.backup, "backup", "", false, "Performs a backup prior to any destructive actions")
+	SavedVarsCopyCmd.Flags().BoolVarP(&flags.dryRun, "dry-run", "", false, "Shows what changes would be made without actually making them")
+}
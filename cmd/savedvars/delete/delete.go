@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	backupCmd "github.com/dyoung522/esotools/cmd/backup/saved_vars"
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	dryRun bool
+}
+
+var (
+	red    = pterm.NewStyle(pterm.FgRed)
+	yellow = pterm.NewStyle(pterm.FgYellow)
+)
+
+// SavedVarsDeleteCmd represents the savedvars delete command
+var SavedVarsDeleteCmd = &cobra.Command{
+	Use:   "delete <file|addon> <path>",
+	Short: "Deletes a single value within a SavedVariables file",
+	Long: `Deletes the value at a path within a SavedVariables file, such as a single broken setting, which the AddOn will
+usually reset to its default the next time it's loaded. Giving only a variable name deletes the whole variable.
+
+The path is written as with "savedvars show", starting with the variable name.
+Everything else within the file is left exactly as it was.
+The file is backed up (to a saved_variables_*.zip file in the current directory) before it's changed.
+
+The game overwrites SavedVariables files when logging out or reloading the UI, so make changes while it isn't running.`,
+	Example: `  esotools savedvars delete MyAddon 'MyAddon_SV.Default.@Account.$AccountWide.position'
+  esotools savedvars delete --dry-run MyAddon MyAddon_SV`,
+	Args: cobra.ExactArgs(2),
+	Run:  execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	path, err := eso.FindSavedVarsFile(AppFs, args[0])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	if !eso.InSavedVariablesDir(path) {
+		fmt.Printf("%q is not within %q, only files within the SavedVariables directory can be changed\n", path, eso.SavedVariablesPath())
+		os.Exit(1)
+	}
+
+	keys, err := eso.ParseLuaPath(args[1])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	document, err := eso.ReadLua(AppFs, path)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	old, err := document.Query(args[1])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	data, err := document.DeleteAt(keys)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s %s = %s\n", red.Sprint("-"), eso.FormatLuaPath(keys), old.Summary())
+
+	if flags.dryRun {
+		yellow.Printf("Would have updated: %q\n", path)
+		return
+	}
+
+	if err := backupCmd.BackupSavedVars(AppFs, filepath.Base(path)); err != nil {
+		red.Println("Not updating", path, "as it couldn't be backed up")
+		os.Exit(2)
+	}
+
+	fmt.Println("Updating:", path)
+	if err := eso.WriteFileAtomic(AppFs, path, data); err != nil {
+		red.Printf("Error updating %s: %s\n", filepath.Base(path), err)
+		os.Exit(2)
+	}
+}
+
+func init() {
+	SavedVarsDeleteCmd.Flags().BoolVarP(&flags.dryRun, "dry-run", "", false, "Shows what changes would be made without actually making them")
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	red    = pterm.NewStyle(pterm.FgRed)
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+	cyan   = pterm.NewStyle(pterm.FgCyan)
+)
+
+var flags struct {
+	backup string
+	path   string
+}
+
+// SavedVarsDiffCmd represents the savedvars diff command
+var SavedVarsDiffCmd = &cobra.Command{
+	Use:   "diff <file|addon> [file|addon]",
+	Short: "Compares SavedVariables files, or a SavedVariables file against a backup",
+	Long: `Compares two SavedVariables files, reporting every key which was added, removed, or changed, along with its old and new values.
+
+Given a single file, the live file is compared against the copy within the latest saved_variables_*.zip backup in the
+current directory which contains it (see "esotools backup savedvars"), or the backup given with --backup.
+
+Files may be given as paths, or as the names of files within the SavedVariables directory (the ".lua" extension is optional).
+Exits with a status of 1 if any differences were found.
+`,
+	Example: `  esotools savedvars diff MyAddon
+  esotools savedvars diff --backup saved_variables_20241001120000.zip MyAddon
+  esotools savedvars diff --path 'MyAddon_SV.Default' MyAddon.lua /path/to/other/MyAddon.lua`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+	var oldName, newName string
+	var oldDocument, newDocument *eso.LuaDocument
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	path, err := eso.FindSavedVarsFile(AppFs, args[0])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	if len(args) > 1 {
+		if flags.backup != "" {
+			fmt.Println("--backup may only be used when comparing a single file")
+			os.Exit(1)
+		}
+
+		newPath, err := eso.FindSavedVarsFile(AppFs, args[1])
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+
+		if oldDocument, err = eso.ReadLua(AppFs, path); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+
+		oldName, newName = path, newPath
+		path = newPath
+	} else {
+		var archive = flags.backup
+
+		if archive == "" {
+			if archive, err = eso.FindSavedVarsBackup(AppFs, ".", filepath.Base(path)); err != nil {
+				fmt.Printf("%s, use --backup to specify one\n", err)
+				os.Exit(2)
+			}
+		}
+
+		if oldDocument, err = eso.ReadSavedVarsBackup(AppFs, archive, filepath.Base(path)); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+
+		oldName, newName = fmt.Sprintf("%s (%s)", filepath.Base(path), archive), path
+	}
+
+	if newDocument, err = eso.ReadLua(AppFs, path); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	var oldValue, newValue = oldDocument.Table(), newDocument.Table()
+	var prefix []string
+
+	if flags.path != "" {
+		if prefix, err = eso.ParseLuaPath(flags.path); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		oldValue, newValue = queryOrNil(oldDocument, flags.path), queryOrNil(newDocument, flags.path)
+
+		if oldValue == nil && newValue == nil {
+			fmt.Printf("%s was not found in either file\n", flags.path)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("Comparing %s with %s\n", cyan.Sprint(oldName), cyan.Sprint(newName))
+
+	var changes []eso.LuaChange
+
+	switch {
+	case oldValue == nil:
+		changes = []eso.LuaChange{{Type: eso.LuaAdded, Path: prefix, New: newValue}}
+	case newValue == nil:
+		changes = []eso.LuaChange{{Type: eso.LuaRemoved, Path: prefix, Old: oldValue}}
+	default:
+		changes = eso.DiffLua(oldValue, newValue, prefix...)
+	}
+
+	if len(changes) == 0 {
+		green.Println("No differences found")
+		return
+	}
+
+	var counts = make(map[eso.LuaChangeType]int)
+
+	for _, change := range changes {
+		counts[change.Type]++
+
+		switch change.Type {
+		case eso.LuaAdded:
+			green.Printf("+ %s = %s\n", change.PathString(), change.New.Summary())
+		case eso.LuaRemoved:
+			red.Printf("- %s = %s\n", change.PathString(), change.Old.Summary())
+		case eso.LuaChanged:
+			yellow.Printf("~ %s: %s -> %s\n", change.PathString(), change.Old.Summary(), change.New.Summary())
+		}
+	}
+
+	fmt.Printf(
+		"\n%d added, %d removed, %d changed\n",
+		counts[eso.LuaAdded],
+		counts[eso.LuaRemoved],
+		counts[eso.LuaChanged],
+	)
+
+	os.Exit(1)
+}
+
+func queryOrNil(document *eso.LuaDocument, path string) *eso.LuaValue {
+	value, err := document.Query(path)
+	if err != nil {
+		return nil
+	}
+
+	return value
+}
+
+func init() {
+	SavedVarsDiffCmd.Flags().StringVarP(&flags.backup, "backup", "b", "", "The backup to compare against (defaults to the latest saved_variables_*.zip in the current directory)")
+	SavedVarsDiffCmd.Flags().StringVarP(&flags.path, "path", "p", "", "Only compare the variable or table at the given path (see \"savedvars show\")")
+}
@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	backupCmd "github.com/dyoung522/esotools/cmd/backup/saved_vars"
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	characters []string
+	backup     bool
+	dryRun     bool
+}
+
+var (
+	red     = pterm.NewStyle(pterm.FgRed)
+	caution = pterm.NewStyle(pterm.BgRed, pterm.FgYellow, pterm.Bold)
+	yellow  = pterm.NewStyle(pterm.FgYellow)
+	green   = pterm.NewStyle(pterm.FgGreen)
+	cyan    = pterm.NewStyle(pterm.FgCyan)
+	blue    = pterm.NewStyle(pterm.FgBlue)
+)
+
+// A SavedVariables file and the characters it holds data for
+type savedVarsFile struct {
+	eso.SavedVarsFile
+	characters []eso.SavedVarsCharacter
+}
+
+// A character and every file holding its data
+type characterUsage struct {
+	character eso.SavedVarsCharacter
+	files     []string
+	size      int
+}
+
+// SavedVarsPruneCmd represents the savedvars prune command
+var SavedVarsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Removes the data of deleted or renamed characters from SavedVariables",
+	Long: `Lists every character with data in the SavedVariables files, and removes the data of the chosen characters.
+
+SavedVariables are stored per account and per character, and the game never removes the data of characters which were
+deleted (or, in older files keyed by name, renamed). Characters may be chosen with --character, by name or id, or
+interactively. Account-wide data is never removed, and the rest of each file is left exactly as it was.`,
+	Example: `  esotools savedvars prune
+  esotools savedvars prune --dry-run --character Dovahkiin --character 8798292047654321`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+	var verbosity = viper.GetInt("verbosity")
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	savedVarFiles, skipped, err := eso.ReadSavedVarsFiles(AppFs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	for _, err := range skipped {
+		fmt.Fprint(os.Stderr, yellow.Sprintf("Skipping %s\n", err))
+	}
+
+	var files []savedVarsFile
+	var usage = make(map[string]*characterUsage)
+	var ids []string
+
+	for _, savedVars := range savedVarFiles {
+		characters := savedVars.Document.Characters()
+		if len(characters) == 0 {
+			continue
+		}
+
+		files = append(files, savedVarsFile{SavedVarsFile: savedVars, characters: characters})
+
+		for _, character := range characters {
+			if _, ok := usage[character.ID()]; !ok {
+				usage[character.ID()] = &characterUsage{character: character}
+				ids = append(ids, character.ID())
+			}
+
+			var u = usage[character.ID()]
+
+			// Prefer the character's name over its id, if any file has recorded it
+			if u.character.Name == u.character.Key {
+				u.character.Name = character.Name
+			}
+
+			if len(u.files) == 0 || u.files[len(u.files)-1] != savedVars.Name() {
+				u.files = append(u.files, savedVars.Name())
+			}
+
+			u.size += character.Size
+		}
+	}
+
+	if len(ids) == 0 {
+		green.Println("No character data found in any SavedVariables files")
+		return
+	}
+
+	fmt.Printf("Found data for %d %s:\n", len(ids), eso.Pluralize("character", len(ids)))
+
+	for _, id := range ids {
+		var u = usage[id]
+
+		fmt.Printf(
+			"%s %s %s %s\n",
+			cyan.Sprintf("%-40s", u.character.String()),
+			blue.Sprintf("%-20s", u.character.Account),
+			yellow.Sprintf("%10s", eso.FormatSize(int64(u.size))),
+			fmt.Sprintf("in %d %s", len(u.files), eso.Pluralize("file", len(u.files))),
+		)
+
+		if verbosity >= 1 {
+			fmt.Printf("  %s\n", strings.Join(u.files, ", "))
+		}
+	}
+
+	fmt.Println()
+
+	selected := selectCharacters(ids, usage)
+	if len(selected) == 0 {
+		return
+	}
+
+	prune(AppFs, files, selected)
+}
+
+// Returns the ids of the characters chosen with --character, or interactively if none were given
+func selectCharacters(ids []string, usage map[string]*characterUsage) map[string]bool {
+	var selected = make(map[string]bool)
+
+	if len(flags.characters) > 0 {
+		for _, name := range flags.characters {
+			var found bool
+
+			for _, id := range ids {
+				if usage[id].character.Matches(name) {
+					selected[id] = true
+					found = true
+				}
+			}
+
+			if !found {
+				red.Printf("No data found for character %q\n", name)
+				os.Exit(1)
+			}
+		}
+
+		return selected
+	}
+
+	var options []string
+	var optionIds = make(map[string]string)
+
+	for _, id := range ids {
+		option := fmt.Sprintf("%s %s", usage[id].character, usage[id].character.Account)
+		options = append(options, option)
+		optionIds[option] = id
+	}
+
+	choices, err := pterm.DefaultInteractiveMultiselect.
+		WithOptions(options).
+		WithMaxHeight(15).
+		Show("Select the characters to remove [space to select, enter to confirm]")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	for _, choice := range choices {
+		selected[optionIds[choice]] = true
+	}
+
+	return selected
+}
+
+func prune(AppFs afero.Fs, files []savedVarsFile, selected map[string]bool) {
+	var removePrompt = fmt.Sprintf("Remove the data of %d %s?", len(selected), eso.Pluralize("character", len(selected)))
+	var totalSaved int
+
+	if flags.dryRun {
+		removePrompt += " [dry-run enabled, no destructive actions will be taken]"
+	}
+
+	if result, _ := pterm.DefaultInteractiveConfirm.Show(removePrompt); !result {
+		return
+	}
+
+	if !flags.backup && !flags.dryRun {
+		savePrompt := caution.Sprint("This opperation is destructive, do you want to make a backup first?")
+
+		if result, _ := pterm.DefaultInteractiveConfirm.Show(savePrompt); result {
+			flags.backup = true
+		}
+	}
+
+	if flags.backup && !flags.dryRun {
+		if err := backupCmd.BackupSavedVars(AppFs); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	for _, file := range files {
+		var characters []eso.SavedVarsCharacter
+
+		for _, character := range file.characters {
+			if selected[character.ID()] {
+				characters = append(characters, character)
+			}
+		}
+
+		if len(characters) == 0 {
+			continue
+		}
+
+		data, err := file.Document.RemoveCharacters(characters...)
+		if err != nil {
+			red.Printf("Error pruning %s: %s\n", file.Name(), err)
+			continue
+		}
+
+		var saved = len(file.Document.Source) - len(data)
+		var summary = fmt.Sprintf(
+			"%s (%s -> %s)",
+			eso.FormatSize(int64(saved)),
+			eso.FormatSize(int64(len(file.Document.Source))),
+			eso.FormatSize(int64(len(data))),
+		)
+
+		if flags.dryRun {
+			yellow.Printf("Would have saved %s in %s\n", summary, file.Name())
+		} else {
+			if err := eso.WriteFileAtomic(AppFs, file.Path, data); err != nil {
+				red.Printf("Error pruning %s: %s\n", file.Name(), err)
+				continue
+			}
+
+			fmt.Printf("Saved %s in %s\n", summary, file.Name())
+		}
+
+		totalSaved += saved
+	}
+
+	green.Printf("\nTotal: %s\n", eso.FormatSize(int64(totalSaved)))
+}
+
+func init() {
+	SavedVarsPruneCmd.Flags().StringSliceVarP(&flags.characters, "character", "c", []string{}, "The name or id of a character to remove (may be given more than once)")
+	SavedVarsPruneCmd.Flags().BoolVarP(&flags.backup, "backup", "", false, "Performs a backup prior to any destructive actions")
+	SavedVarsPruneCmd.Flags().BoolVarP(&flags.dryRun, "dry-run", "", false, "Shows what changes would be made without actually making them")
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	sub8 "github.com/dyoung522/esotools/cmd/savedvars/anonymize"
+	sub4 "github.com/dyoung522/esotools/cmd/savedvars/copy"
+	sub7 "github.com/dyoung522/esotools/cmd/savedvars/delete"
+	sub2 "github.com/dyoung522/esotools/cmd/savedvars/diff"
+	sub3 "github.com/dyoung522/esotools/cmd/savedvars/prune"
+	sub6 "github.com/dyoung522/esotools/cmd/savedvars/set"
+	sub1 "github.com/dyoung522/esotools/cmd/savedvars/show"
+	sub5 "github.com/dyoung522/esotools/cmd/savedvars/stats"
+	"github.com/spf13/cobra"
+)
+
+// SavedVarsCmd represents the savedvars command
+var SavedVarsCmd = &cobra.Command{
+	Use:   "savedvars",
+	Short: "Various SavedVariables commands",
+}
+
+func init() {
+	SavedVarsCmd.AddCommand(sub1.SavedVarsShowCmd)
+	SavedVarsCmd.AddCommand(sub2.SavedVarsDiffCmd)
+	SavedVarsCmd.AddCommand(sub3.SavedVarsPruneCmd)
+	SavedVarsCmd.AddCommand(sub4.SavedVarsCopyCmd)
+	SavedVarsCmd.AddCommand(sub5.SavedVarsStatsCmd)
+	SavedVarsCmd.AddCommand(sub6.SavedVarsSetCmd)
+	SavedVarsCmd.AddCommand(sub7.SavedVarsDeleteCmd)
+	SavedVarsCmd.AddCommand(sub8.SavedVarsAnonymizeCmd)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	backupCmd "github.com/dyoung522/esotools/cmd/backup/saved_vars"
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	string bool
+	dryRun bool
+}
+
+var (
+	red    = pterm.NewStyle(pterm.FgRed)
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+)
+
+// SavedVarsSetCmd represents the savedvars set command
+var SavedVarsSetCmd = &cobra.Command{
+	Use:   "set <file|addon> <path> <value>",
+	Short: "Sets a single value within a SavedVariables file",
+	Long: `Sets the value at a path within a SavedVariables file, creating any missing tables along the way.
+
+The path is written as with "savedvars show", starting with the variable name. The value is written as in Lua:
+a number, true, false, a quoted string, or a table such as '{ ["x"] = 1 }'. Use --string to set a string without quoting it.
+
+The value is written as the game would write it, and everything else within the file is left exactly as it was.
+The file is backed up (to a saved_variables_*.zip file in the current directory) before it's changed.
+
+The game overwrites SavedVariables files when logging out or reloading the UI, so make changes while it isn't running.`,
+	Example: `  esotools savedvars set MyAddon 'MyAddon_SV.Default.@Account.$AccountWide.enabled' false
+  esotools savedvars set MyAddon 'MyAddon_SV.Default.@Account.$AccountWide.position' '{ ["x"] = 0, ["y"] = 0 }'
+  esotools savedvars set --string MyAddon 'MyAddon_SV.Default.@Account.$AccountWide.home' "Vivec City"`,
+	Args: cobra.ExactArgs(3),
+	Run:  execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+	var value *eso.LuaValue
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	path, err := eso.FindSavedVarsFile(AppFs, args[0])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	if !eso.InSavedVariablesDir(path) {
+		fmt.Printf("%q is not within %q, only files within the SavedVariables directory can be changed\n", path, eso.SavedVariablesPath())
+		os.Exit(1)
+	}
+
+	keys, err := eso.ParseLuaPath(args[1])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if flags.string {
+		value = eso.NewLuaString(args[2])
+	} else if value, err = eso.ParseLuaValue(args[2]); err != nil {
+		fmt.Printf("invalid value %q: %s (use --string to set it as a string)\n", args[2], err)
+		os.Exit(1)
+	}
+
+	if value.Kind == eso.LuaNil {
+		fmt.Println("the game never writes nil values, use \"esotools savedvars delete\" to remove a value instead")
+		os.Exit(1)
+	}
+
+	document, err := eso.ReadLua(AppFs, path)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	old, _ := document.Query(args[1])
+
+	if old != nil && eso.LuaEqual(old, value) {
+		green.Printf("%s is already %s\n", eso.FormatLuaPath(keys), value.Summary())
+		return
+	}
+
+	data, err := document.SetAt(keys, value)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if old == nil {
+		fmt.Printf("%s %s = %s\n", green.Sprint("+"), eso.FormatLuaPath(keys), value.Summary())
+	} else {
+		fmt.Printf("%s %s: %s -> %s\n", yellow.Sprint("~"), eso.FormatLuaPath(keys), old.Summary(), value.Summary())
+	}
+
+	if flags.dryRun {
+		yellow.Printf("Would have updated: %q\n", path)
+		return
+	}
+
+	if err := backupCmd.BackupSavedVars(AppFs, filepath.Base(path)); err != nil {
+		red.Println("Not updating", path, "as it couldn't be backed up")
+		os.Exit(2)
+	}
+
+	fmt.Println("Updating:", path)
+	if err := eso.WriteFileAtomic(AppFs, path, data); err != nil {
+		red.Printf("Error updating %s: %s\n", filepath.Base(path), err)
+		os.Exit(2)
+	}
+}
+
+func init() {
+	SavedVarsSetCmd.Flags().BoolVarP(&flags.string, "string", "s", false, "Sets the value as a string, without needing to quote it")
+	SavedVarsSetCmd.Flags().BoolVarP(&flags.dryRun, "dry-run", "", false, "Shows what changes would be made without actually making them")
+}
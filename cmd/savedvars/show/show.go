@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	red    = pterm.NewStyle(pterm.FgRed)
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+	cyan   = pterm.NewStyle(pterm.FgCyan)
+	blue   = pterm.NewStyle(pterm.FgBlue)
+)
+
+var flags struct {
+	format string
+	depth  int
+}
+
+// SavedVarsShowCmd represents the savedvars show command
+var SavedVarsShowCmd = &cobra.Command{
+	Use:   "show <file|addon> [path]",
+	Short: "Pretty-prints a SavedVariables file",
+	Long: `Pretty-prints a SavedVariables file as an indented tree, JSON, or YAML.
+
+The file may be given as a path, or as the name of a file within the SavedVariables directory (the ".lua" extension is optional).
+
+An optional path narrows the output to a single variable or nested table. Each part of the path is separated by a dot,
+starting with the variable name; keys containing dots may be written in brackets, e.g. MyAddon_SV["key.with.dots"].
+`,
+	Example: `  esotools savedvars show MyAddon
+  esotools savedvars show MyAddon 'MyAddon_SV.Default.@Account.$AccountWide.settings'
+  esotools savedvars show --format json MyAddon MyAddon_SV`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	path, err := eso.FindSavedVarsFile(AppFs, args[0])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	document, err := eso.ReadLua(AppFs, path)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	var value = document.Table()
+
+	if len(args) > 1 {
+		if value, err = document.Query(args[1]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	switch flags.format {
+	case "json":
+		output, err := value.ToJson()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+
+		fmt.Println(output)
+	case "yaml":
+		output, err := value.ToYAML()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+
+		fmt.Print(output)
+	case "tree":
+		printTree(value)
+	default:
+		fmt.Printf("unknown format %q, expected one of tree, json, or yaml\n", flags.format)
+		os.Exit(1)
+	}
+}
+
+// Prints each field of a table on its own line, indenting nested tables beneath their key
+func printTree(value *eso.LuaValue) {
+	if value.Kind != eso.LuaTable {
+		fmt.Println(valueString(value))
+		return
+	}
+
+	if len(value.Fields) == 0 {
+		fmt.Println("{}")
+		return
+	}
+
+	printFields(value, 0)
+}
+
+func printFields(table *eso.LuaValue, depth int) {
+	var indent = strings.Repeat("  ", depth)
+
+	for _, field := range table.Fields {
+		var key = cyan.Sprint(field.KeyString())
+		var value = field.Value
+
+		switch {
+		case value.Kind != eso.LuaTable:
+			fmt.Printf("%s%s = %s\n", indent, key, valueString(value))
+		case len(value.Fields) == 0:
+			fmt.Printf("%s%s = {}\n", indent, key)
+		case flags.depth > 0 && depth+1 >= flags.depth:
+			fmt.Printf("%s%s = %s\n", indent, key, pterm.Gray(value.Summary()))
+		default:
+			fmt.Printf("%s%s\n", indent, key)
+			printFields(value, depth+1)
+		}
+	}
+}
+
+func valueString(value *eso.LuaValue) string {
+	switch value.Kind {
+	case eso.LuaString:
+		return green.Sprint(value.Summary())
+	case eso.LuaNumber:
+		return yellow.Sprint(value.Summary())
+	case eso.LuaBoolean:
+		return blue.Sprint(value.Summary())
+	case eso.LuaNil:
+		return red.Sprint(value.Summary())
+	default:
+		return value.Summary()
+	}
+}
+
+func init() {
+	SavedVarsShowCmd.Flags().StringVarP(&flags.format, "format", "f", "tree", "Output format: tree, json, or yaml")
+	SavedVarsShowCmd.Flags().IntVarP(&flags.depth, "depth", "d", 0, "Limits how many levels of nested tables are printed in the tree format (0 for no limit)")
+}
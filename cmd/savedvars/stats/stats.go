@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var flags struct {
+	top int
+}
+
+var (
+	red    = pterm.NewStyle(pterm.FgRed)
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+	cyan   = pterm.NewStyle(pterm.FgCyan)
+	blue   = pterm.NewStyle(pterm.FgBlue)
+)
+
+// SavedVarsStatsCmd represents the savedvars stats command
+var SavedVarsStatsCmd = &cobra.Command{
+	Use:   "stats [file|addon...]",
+	Short: "Reports where the space in SavedVariables files goes",
+	Long: `Reports the size of each SavedVariables file (or only those given), largest first, along with the installed AddOn which owns it.
+
+For each file, the largest tables within it are listed, along with how much of it belongs to each account and character.
+Tables which only wrap a single, larger table (such as "Default") are skipped in favor of the table they wrap.
+Finally, the space used by each account and character across every file is totaled.
+
+Large SavedVariables files slow down logging in and reloading the UI, as the game reads every one of them.`,
+	Example: `  esotools savedvars stats
+  esotools savedvars stats --top 10 MyAddon`,
+	Run: execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	var AppFs = afero.NewOsFs()
+
+	if flags.top < 0 {
+		fmt.Println("--top must not be negative")
+		os.Exit(1)
+	}
+
+	addons, errs := eso.Run()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		os.Exit(2)
+	}
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	files, skipped, err := eso.ReadSavedVarsFiles(AppFs, args...)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	for _, err := range skipped {
+		fmt.Fprint(os.Stderr, yellow.Sprintf("Skipping %s\n", err))
+	}
+
+	if len(files) == 0 {
+		green.Println("No SavedVariables files found")
+		return
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+
+	var totalSize int64
+	var accountSizes = make(map[string]int)
+	var characterSizes = make(map[string]int)
+	var characters = make(map[string]eso.SavedVarsCharacter)
+
+	for _, file := range files {
+		totalSize += file.Size
+
+		usage := file.Document.AccountUsage()
+		printFile(addons, file, usage)
+
+		for _, usage := range usage {
+			accountSizes[usage.Account] += usage.Size
+
+			for _, character := range usage.Characters {
+				characterSizes[character.ID()] += character.Size
+
+				if _, ok := characters[character.ID()]; !ok || characters[character.ID()].Name == character.Key {
+					characters[character.ID()] = character
+				}
+			}
+		}
+	}
+
+	yellow.Printf("Total: %s in %d %s\n", eso.FormatSize(totalSize), len(files), eso.Pluralize("file", len(files)))
+
+	for _, account := range sortedBySize(accountSizes) {
+		fmt.Printf("  %s %s\n", cyan.Sprintf("%10s", eso.FormatSize(int64(accountSizes[account]))), blue.Sprint(account))
+
+		for _, id := range sortedBySize(characterSizes) {
+			if character := characters[id]; character.Account == account {
+				fmt.Printf("    %s %s\n", cyan.Sprintf("%10s", eso.FormatSize(int64(characterSizes[id]))), character)
+			}
+		}
+	}
+}
+
+// Prints the size, owner, largest tables, and accounts of a file
+func printFile(addons eso.AddOns, file eso.SavedVarsFile, usage []eso.SavedVarsAccountUsage) {
+	var owner string
+
+	if key, ok := addons.SavedVarsOwner(file.Name(), file.Document.Names()); ok {
+		owner = "owned by " + green.Sprint(key)
+	} else {
+		owner = red.Sprint("not owned by any installed AddOn")
+	}
+
+	fmt.Printf("%s %s, %s\n", yellow.Add(*pterm.Bold.ToStyle()).Sprint(file.Name()), cyan.Sprint(eso.FormatSize(file.Size)), owner)
+
+	if largest := file.Document.LargestSubtrees(flags.top); len(largest) > 0 {
+		fmt.Println("  Largest tables:")
+
+		for _, subtree := range largest {
+			fmt.Printf(
+				"    %s %s %s\n",
+				cyan.Sprintf("%10s", eso.FormatSize(int64(subtree.Size))),
+				eso.FormatLuaPath(subtree.Path),
+				pterm.Gray(fmt.Sprintf("(%d %s)", subtree.Fields, eso.Pluralize("field", subtree.Fields))),
+			)
+		}
+	}
+
+	if len(usage) > 0 {
+		fmt.Println("  Accounts:")
+
+		for _, account := range usage {
+			fmt.Printf(
+				"    %s %s %s\n",
+				cyan.Sprintf("%10s", eso.FormatSize(int64(account.Size))),
+				blue.Sprint(account.Account),
+				pterm.Gray(fmt.Sprintf("(%s: %s)", eso.AccountWide, eso.FormatSize(int64(account.AccountWide)))),
+			)
+
+			for _, character := range account.Characters {
+				fmt.Printf("      %s %s\n", cyan.Sprintf("%10s", eso.FormatSize(int64(character.Size))), character)
+			}
+		}
+	}
+
+	fmt.Println()
+}
+
+// Returns the keys of sizes, largest first
+func sortedBySize(sizes map[string]int) []string {
+	var keys []string
+
+	for key := range sizes {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if sizes[keys[i]] != sizes[keys[j]] {
+			return sizes[keys[i]] > sizes[keys[j]]
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
+func init() {
+	SavedVarsStatsCmd.Flags().IntVarP(&flags.top, "top", "n", 5, "The number of largest tables to list for each file")
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	yellow = pterm.NewStyle(pterm.FgYellow)
+	green  = pterm.NewStyle(pterm.FgGreen)
+	cyan   = pterm.NewStyle(pterm.FgCyan)
+	blue   = pterm.NewStyle(pterm.FgBlue)
+)
+
+var flags struct {
+	requiredOnly bool
+	limit        int
+}
+
+// WhyCmd represents the why command
+var WhyCmd = &cobra.Command{
+	Use:   "why <addon>",
+	Short: "Shows why an ESO AddOn is installed",
+	Long: `Shows every chain of AddOns depending on the given AddOn, up to the top-level AddOns which nothing else depends upon.
+
+Required chains mean the top-level AddOn won't load without it, while optional chains only lose a feature.
+If nothing requires the AddOn, it's safe to remove.
+
+Widely shared libraries can be reached by a great many chains, so only the first 100 are shown (preferring required
+chains) unless another --limit is given.`,
+	Example: `  esotools why LibAddonMenu-2.0`,
+	Args:    cobra.ExactArgs(1),
+	Run:     execute,
+}
+
+func execute(cmd *cobra.Command, args []string) {
+	addons, errs := eso.Run()
+	if len(errs) > 0 {
+		for _, e := range errs {
+			fmt.Println(e)
+		}
+		os.Exit(2)
+	}
+
+	if viper.GetBool("noColor") {
+		pterm.DisableColor()
+	}
+
+	graph := eso.NewDependencyGraph(addons)
+
+	node, exists := graph.Find(args[0])
+	if !exists {
+		fmt.Printf("AddOn %q is not installed, and nothing depends on it\n", args[0])
+		os.Exit(1)
+	}
+
+	chains, truncated := graph.ChainsTo(node.Key, flags.limit)
+
+	var required, optional []eso.DependencyChain
+	for _, chain := range chains {
+		if chain.Required() {
+			required = append(required, chain)
+		} else if !flags.requiredOnly {
+			optional = append(optional, chain)
+		}
+	}
+
+	if node.Missing {
+		yellow.Printf("%s is not installed\n\n", node.Key)
+	}
+
+	if len(required) == 0 && len(optional) == 0 {
+		if flags.requiredOnly && node.Dependents > 0 {
+			green.Printf("Nothing requires %s, it is only an optional dependency\n", node.Key)
+		} else {
+			green.Printf("Nothing depends on %s, it was installed on its own\n", node.Key)
+		}
+		return
+	}
+
+	printChains("Required by", required)
+	printChains("Optionally used by", optional)
+
+	var atLeast string
+
+	if truncated {
+		yellow.Printf("Only the first %d chains are shown, use --limit 0 to show them all\n", len(chains))
+		atLeast = "at least "
+	}
+
+	switch {
+	case len(required) > 0:
+		cyan.Printf("%s is required by %s%d top-level %s\n", node.Key, atLeast, len(roots(required)), eso.Pluralize("AddOn", len(roots(required))))
+	case !truncated:
+		green.Printf("Nothing requires %s, removing it only disables optional features of %d %s\n", node.Key, len(roots(optional)), eso.Pluralize("AddOn", len(roots(optional))))
+	}
+}
+
+func printChains(heading string, chains []eso.DependencyChain) {
+	if len(chains) == 0 {
+		return
+	}
+
+	blue.Println(heading + ":")
+	for _, chain := range chains {
+		fmt.Printf("  %s\n", chain)
+	}
+	fmt.Println()
+}
+
+// Returns the distinct top-level AddOns at the start of the chains.
+func roots(chains []eso.DependencyChain) map[string]bool {
+	roots := map[string]bool{}
+
+	for _, chain := range chains {
+		roots[chain.Root()] = true
+	}
+
+	return roots
+}
+
+func init() {
+	WhyCmd.Flags().BoolVarP(&flags.requiredOnly, "required-only", "R", false, "Only show chains of required dependencies")
+	WhyCmd.Flags().IntVarP(&flags.limit, "limit", "l", 100, "The most chains to show (0 for no limit)")
+}
@@ -14,6 +14,7 @@ require (
 	github.com/stretchr/testify v1.9.0
 	golang.org/x/sys v0.25.0
 	golang.org/x/text v0.18.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -49,5 +50,4 @@ require (
 	golang.org/x/net v0.28.0 // indirect
 	golang.org/x/term v0.24.0 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
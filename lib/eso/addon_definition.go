@@ -6,10 +6,15 @@ import (
 	"strings"
 )
 
+// ManifestExtensions lists the manifest file extensions the game recognizes, in order of precedence.
+// When a directory contains more than one manifest, the game loads the first one found in this list.
+var ManifestExtensions = []string{".addon", ".txt"}
+
 // AddOnDefinition represents a single ESO add-on definition.
 type AddOnDefinition struct {
-	Name string // Name of the add-on file (without the .txt extension).
-	Dir  string // Directory where the add-on file is located.
+	Name     string // Name of the add-on manifest file (including its .addon or .txt extension).
+	Dir      string // Directory where the add-on file is located.
+	Shadowed string // Name of a lower precedence manifest in the same directory, which the game ignores.
 }
 
 // String returns a string representation of the AddOnDefinition.
@@ -19,13 +24,33 @@ func (AD AddOnDefinition) String() string {
 }
 
 // Path returns the full path to the add-on file.
-// It joins the directory (Dir) and the name (with .txt extension) of the add-on file.
+// It joins the directory (Dir) and the name (with its extension) of the add-on file.
 func (AD AddOnDefinition) Path() string {
 	return filepath.Join(AD.Dir, AD.Name)
 }
 
 // Key returns the unique identifier of the add-on.
-// It trims the .txt extension from the name and converts it to a key using the ToKey function from the eso package.
+// It trims the manifest extension from the name and converts it to a key using the ToKey function from the eso package.
 func (AD AddOnDefinition) Key() string {
-	return ToKey(strings.TrimSuffix(AD.Name, ".txt"))
+	if AD.Precedence() < 0 {
+		return ToKey(AD.Name)
+	}
+
+	return ToKey(strings.TrimSuffix(AD.Name, filepath.Ext(AD.Name)))
+}
+
+// Precedence returns the position of the manifest's extension in ManifestExtensions (lower wins),
+// or -1 if the file isn't a recognized manifest.
+func (AD AddOnDefinition) Precedence() int {
+	return manifestPrecedence(AD.Name)
+}
+
+func manifestPrecedence(name string) int {
+	for i, ext := range ManifestExtensions {
+		if filepath.Ext(name) == ext {
+			return i
+		}
+	}
+
+	return -1
 }
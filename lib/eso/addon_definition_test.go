@@ -175,3 +175,42 @@ func TestAddOnDefinition_Key_NonASCIICharacters(t *testing.T) {
 		t.Errorf("expected key '%s' for name '%s', but got: '%s'", expectedKey, addOnDef.Name, key)
 	}
 }
+
+func TestAddOnDefinition_Key_AddonExtension(t *testing.T) {
+	// Arrange
+	addOnDef := eso.AddOnDefinition{
+		Name: "MyAddon.addon",
+		Dir:  "path/to/addon",
+	}
+
+	// Act
+	key := addOnDef.Key()
+
+	// Assert
+	expectedKey := "MyAddon"
+	if key != expectedKey {
+		t.Errorf("expected key '%s' for name '%s', but got: '%s'", expectedKey, addOnDef.Name, key)
+	}
+}
+
+func TestAddOnDefinition_Precedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect int
+	}{
+		{name: "MyAddon.addon", expect: 0},
+		{name: "MyAddon.txt", expect: 1},
+		{name: "MyAddon.lua", expect: -1},
+		{name: "MyAddon", expect: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addOnDef := eso.AddOnDefinition{Name: tt.name, Dir: "path/to/addon"}
+
+			if actual := addOnDef.Precedence(); actual != tt.expect {
+				t.Errorf("expected precedence %d for name '%s', but got: %d", tt.expect, tt.name, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package eso
+
+import (
+	"sort"
+)
+
+// AddOnNode represents a single installed copy of an AddOn within the AddOns directory tree.
+type AddOnNode struct {
+	AddOn    AddOn
+	Loaded   bool         // True if this is the copy the game will load
+	Children []*AddOnNode // AddOns embedded within this AddOn's directory
+}
+
+// Instances returns every installed copy of every AddOn, sorted by directory.
+func (A AddOns) Instances() []AddOn {
+	instances := []AddOn{}
+
+	for _, addon := range A {
+		instances = append(instances, addon.Instances()...)
+	}
+
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Dir() < instances[j].Dir() })
+
+	return instances
+}
+
+// Tree returns the installed AddOns arranged by how they're nested within each other's directories.
+// Every installed copy of an AddOn is included, with the copy the game will load marked as Loaded.
+func (A AddOns) Tree() []*AddOnNode {
+	var roots []*AddOnNode
+	var instances = A.Instances()
+	var nodes = make(map[string]*AddOnNode, len(instances))
+
+	for _, addon := range instances {
+		nodes[addon.Dir()] = &AddOnNode{AddOn: addon, Loaded: A[addon.Key()].meta.dir == addon.Dir()}
+	}
+
+	for _, addon := range instances {
+		node := nodes[addon.Dir()]
+
+		if parent, found := findParent(instances, addon); found {
+			nodes[parent.Dir()].Children = append(nodes[parent.Dir()].Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	return roots
+}
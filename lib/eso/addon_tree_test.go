@@ -0,0 +1,107 @@
+package eso_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nestedAddOnsFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	return addOnsFs(t, map[string]string{
+		"AddOns/LibAddonMenu-2.0/LibAddonMenu-2.0.txt":                  "## Title: LibAddonMenu\n## AddOnVersion: 30\n",
+		"AddOns/MyAddon/MyAddon.txt":                                    "## Title: My Addon\n",
+		"AddOns/MyAddon/Libs/LibAddonMenu-2.0/LibAddonMenu-2.0.txt":     "## Title: LibAddonMenu\n## AddOnVersion: 35\n",
+		"AddOns/OtherAddon/OtherAddon.txt":                              "## Title: Other Addon\n",
+		"AddOns/OtherAddon/LibAddonMenu-2.0/LibAddonMenu-2.0.txt":       "## Title: LibAddonMenu\n## AddOnVersion: 32\n",
+		"AddOns/OtherAddon/Modules/OtherModule/OtherModule.txt":         "## Title: Other Module\n",
+		"AddOns/OtherAddon/Modules/OtherModule/LibNested/LibNested.txt": "## Title: Lib Nested\n",
+	})
+}
+
+func TestGetAddOns_LoadsHighestAddOnVersion(t *testing.T) {
+	addons, errs := eso.GetAddOns(nestedAddOnsFs(t))
+	require.Empty(t, errs)
+
+	lam, found := addons.Find("LibAddonMenu-2.0")
+	require.True(t, found)
+
+	assert.Equal(t, "35", lam.AddOnVersion)
+	assert.Equal(t, filepath.FromSlash("/MyAddon/Libs/LibAddonMenu-2.0"), lam.Dir())
+	assert.Equal(t, "MyAddon", lam.Parent())
+	assert.True(t, lam.IsDependency())
+
+	require.Len(t, lam.Copies(), 2)
+	assert.Len(t, lam.Instances(), 3)
+	assert.Empty(t, lam.Instances()[0].Copies())
+}
+
+func TestGetAddOns_Parents(t *testing.T) {
+	addons, errs := eso.GetAddOns(nestedAddOnsFs(t))
+	require.Empty(t, errs)
+
+	assert.Equal(t, "", addons.Get("MyAddon").Parent())
+	assert.Equal(t, "OtherAddon", addons.Get("OtherModule").Parent())
+	assert.Equal(t, "OtherModule", addons.Get("LibNested").Parent())
+	assert.False(t, addons.Get("MyAddon").IsSubmodule())
+	assert.True(t, addons.Get("LibNested").IsSubmodule())
+	assert.Equal(t, 4, addons.Get("LibNested").Depth())
+}
+
+func TestGetAddOns_PrefersTopLevelOnVersionTie(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	viper.Set("eso_home", "/tmp/eso")
+
+	_ = afero.WriteFile(fs, filepath.Join(eso.AddOnsPath(), "Host", "Host.txt"), []byte("## Title: Host\n"), 0644)
+	_ = afero.WriteFile(fs, filepath.Join(eso.AddOnsPath(), "Host", "LibTie", "LibTie.txt"), []byte("## AddOnVersion: 5\n"), 0644)
+	_ = afero.WriteFile(fs, filepath.Join(eso.AddOnsPath(), "LibTie", "LibTie.txt"), []byte("## AddOnVersion: 5\n"), 0644)
+
+	addons, errs := eso.GetAddOns(fs)
+	require.Empty(t, errs)
+
+	libTie := addons.Get("LibTie")
+	assert.Equal(t, filepath.FromSlash("/LibTie"), libTie.Dir())
+}
+
+func TestAddOns_Tree(t *testing.T) {
+	addons, errs := eso.GetAddOns(nestedAddOnsFs(t))
+	require.Empty(t, errs)
+
+	tree := addons.Tree()
+	require.Len(t, tree, 3)
+
+	assert.Equal(t, "LibAddonMenu-2.0", tree[0].AddOn.Key())
+	assert.False(t, tree[0].Loaded)
+	assert.Empty(t, tree[0].Children)
+
+	assert.Equal(t, "MyAddon", tree[1].AddOn.Key())
+	require.Len(t, tree[1].Children, 1)
+	assert.Equal(t, "LibAddonMenu-2.0", tree[1].Children[0].AddOn.Key())
+	assert.True(t, tree[1].Children[0].Loaded)
+
+	assert.Equal(t, "OtherAddon", tree[2].AddOn.Key())
+	require.Len(t, tree[2].Children, 2)
+	assert.False(t, tree[2].Children[0].Loaded)
+	assert.Equal(t, "OtherModule", tree[2].Children[1].AddOn.Key())
+	require.Len(t, tree[2].Children[1].Children, 1)
+	assert.Equal(t, "LibNested", tree[2].Children[1].Children[0].AddOn.Key())
+}
+
+func TestPrint_ShowsSubmodulesWithTopLevelCopies(t *testing.T) {
+	addons, errs := eso.GetAddOns(nestedAddOnsFs(t))
+	require.Empty(t, errs)
+
+	viper.Set("noDeps", false)
+	viper.Set("noLibs", false)
+
+	output := addons.Print("simple")
+
+	assert.Contains(t, output, "LibAddonMenu")
+	assert.NotContains(t, output, "Lib Nested")
+}
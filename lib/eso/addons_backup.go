@@ -0,0 +1,73 @@
+package eso
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+// BackupAddOns creates a ZIP file in the current directory containing the given directories, which are relative to
+// the AddOns directory, and returns its name. Paths within the archive are also relative to the AddOns directory.
+func BackupAddOns(AppFs afero.Fs, dirs ...string) (string, error) {
+	verbosity := viper.GetInt("verbosity")
+	addonsPath := AddOnsPath()
+
+	t := time.Now()
+	archiveTime := fmt.Sprintf("%d%02d%02d%02d%02d%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+	archiveFileName := fmt.Sprintf("addons_%s.zip", archiveTime)
+
+	archiveFile, err := AppFs.Create(archiveFileName)
+	if err != nil {
+		return "", err
+	}
+
+	defer archiveFile.Close()
+
+	if verbosity >= 1 {
+		fmt.Printf("Backing up AddOns to %s\n", archiveFileName)
+	}
+
+	zipWriter := zip.NewWriter(archiveFile)
+
+	for _, dir := range dirs {
+		err = afero.Walk(AppFs, filepath.Join(addonsPath, dir), func(path string, info fs.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+
+			name, err := filepath.Rel(addonsPath, path)
+			if err != nil {
+				return err
+			}
+
+			if verbosity >= 2 {
+				fmt.Printf("Adding %s to %s\n", name, archiveFileName)
+			}
+
+			fileData, err := afero.ReadFile(AppFs, path)
+			if err != nil {
+				return err
+			}
+
+			zipFile, err := zipWriter.Create(filepath.ToSlash(name))
+			if err != nil {
+				return err
+			}
+
+			_, err = zipFile.Write(fileData)
+			return err
+		})
+
+		if err != nil {
+			zipWriter.Close()
+			return archiveFileName, err
+		}
+	}
+
+	return archiveFileName, zipWriter.Close()
+}
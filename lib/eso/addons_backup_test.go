@@ -0,0 +1,82 @@
+package eso_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns the contents of each file within the ZIP file at path
+func readZip(t *testing.T, fs afero.Fs, path string) map[string]string {
+	t.Helper()
+
+	data, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, entry := range reader.File {
+		file, err := entry.Open()
+		require.NoError(t, err)
+		contents, err := io.ReadAll(file)
+		require.NoError(t, err)
+		file.Close()
+
+		files[entry.Name] = string(contents)
+	}
+
+	return files
+}
+
+func TestBackupAddOns(t *testing.T) {
+	fs := addOnsFs(t, map[string]string{
+		"AddOns/MyMap/MyMap.txt":         "## Title: My Map\n",
+		"AddOns/MyMap/Lib/LibGPS.txt":    "## Title: LibGPS\n",
+		"AddOns/Other/Other.txt":         "## Title: Other\n",
+		"AddOns/Unrelated/Unrelated.txt": "## Title: Unrelated\n",
+	})
+
+	archive, err := eso.BackupAddOns(fs, "/MyMap", "Other")
+	require.NoError(t, err)
+	assert.Regexp(t, `^addons_\d{14}\.zip$`, archive)
+
+	assert.Equal(t, map[string]string{
+		"MyMap/MyMap.txt":      "## Title: My Map\n",
+		"MyMap/Lib/LibGPS.txt": "## Title: LibGPS\n",
+		"Other/Other.txt":      "## Title: Other\n",
+	}, readZip(t, fs, archive))
+}
+
+func TestBackupAddOns_All(t *testing.T) {
+	fs := addOnsFs(t, map[string]string{
+		"AddOns/MyMap/MyMap.txt": "## Title: My Map\n",
+		"AddOns/Other/Other.txt": "## Title: Other\n",
+	})
+
+	archive, err := eso.BackupAddOns(fs, "")
+	require.NoError(t, err)
+
+	var names []string
+	for name := range readZip(t, fs, archive) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	assert.Equal(t, []string{"MyMap/MyMap.txt", "Other/Other.txt"}, names)
+}
+
+func TestBackupAddOns_Missing(t *testing.T) {
+	fs := addOnsFs(t, nil)
+
+	_, err := eso.BackupAddOns(fs, "Unknown")
+	assert.Error(t, err)
+}
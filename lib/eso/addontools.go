@@ -2,6 +2,7 @@ package eso
 
 import (
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"strings"
 
@@ -32,6 +33,22 @@ func SavedVariablesPath() string {
 	return filepath.Join(filepath.Clean(ESOHome()), "live", "SavedVariables")
 }
 
+// InSavedVariablesDir returns whether path is directly within the SavedVariables directory, which is what
+// "esotools backup savedvars" backs up.
+func InSavedVariablesDir(path string) bool {
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return false
+	}
+
+	savedVarsPath, err := filepath.Abs(SavedVariablesPath())
+	if err != nil {
+		return false
+	}
+
+	return dir == savedVarsPath
+}
+
 func Pluralize(s string, c int) string {
 	var pluralize = pluralize.NewClient()
 
@@ -42,6 +59,82 @@ func Pluralize(s string, c int) string {
 	return pluralize.Plural(s)
 }
 
+// FormatSize returns a byte count in human readable form, e.g. "1.5 MB".
+func FormatSize(bytes int64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// DiskUsage returns the total size of every file within path.
+func DiskUsage(AppFs afero.Fs, path string) (int64, error) {
+	var size int64
+
+	err := afero.Walk(AppFs, path, func(_ string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
+
+// WriteFileAtomic replaces the contents of path with data, by writing a temporary file alongside it and renaming it
+// into place, so that the original is never left half-written. The original file's permissions are kept.
+func WriteFileAtomic(AppFs afero.Fs, path string, data []byte) error {
+	var mode fs.FileMode = 0644
+
+	if info, err := AppFs.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	file, err := afero.TempFile(AppFs, filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error writing %q: %w", path, err)
+	}
+
+	var tempPath = file.Name()
+
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = AppFs.Chmod(tempPath, mode)
+	}
+
+	if err == nil {
+		err = AppFs.Rename(tempPath, path)
+	}
+
+	if err != nil {
+		_ = AppFs.Remove(tempPath)
+		return fmt.Errorf("error writing %q: %w", path, err)
+	}
+
+	return nil
+}
+
 func ValidateESOHOME() error {
 	verbosity := viper.GetInt("verbosity")
 	esoHome := ESOHome()
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -83,6 +84,15 @@ func TestSavedVariablesPath_WithEmptyESOHome(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestInSavedVariablesDir(t *testing.T) {
+	viper.Set("eso_home", "/home/user/eso")
+
+	assert.True(t, eso.InSavedVariablesDir(filepath.Join(eso.SavedVariablesPath(), "MyAddon.lua")))
+	assert.False(t, eso.InSavedVariablesDir(filepath.Join(eso.SavedVariablesPath(), "Backups", "MyAddon.lua")))
+	assert.False(t, eso.InSavedVariablesDir(filepath.Join(eso.AddOnsPath(), "MyAddon.lua")))
+	assert.False(t, eso.InSavedVariablesDir("MyAddon.lua"))
+}
+
 func TestPluralize_WordEndingInS(t *testing.T) {
 	assert := assert.New(t)
 
@@ -154,3 +164,50 @@ func TestPluralize(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "0 B", eso.FormatSize(0))
+	assert.Equal(t, "1023 B", eso.FormatSize(1023))
+	assert.Equal(t, "1.0 KB", eso.FormatSize(1024))
+	assert.Equal(t, "1.5 MB", eso.FormatSize(1536*1024))
+	assert.Equal(t, "2.0 GB", eso.FormatSize(2*1024*1024*1024))
+}
+
+func TestDiskUsage(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	_ = afero.WriteFile(fs, "/addon/a.lua", []byte("12345"), 0644)
+	_ = afero.WriteFile(fs, "/addon/sub/b.lua", []byte("123"), 0644)
+
+	size, err := eso.DiskUsage(fs, "/addon")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), size)
+
+	_, err = eso.DiskUsage(fs, "/missing")
+	assert.Error(t, err)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	_ = afero.WriteFile(fs, "/sv/MyAddon.lua", []byte("old"), 0600)
+
+	err := eso.WriteFileAtomic(fs, "/sv/MyAddon.lua", []byte("new contents"))
+	assert.NoError(t, err)
+
+	data, _ := afero.ReadFile(fs, "/sv/MyAddon.lua")
+	assert.Equal(t, "new contents", string(data))
+
+	info, _ := fs.Stat("/sv/MyAddon.lua")
+	assert.Equal(t, "-rw-------", info.Mode().Perm().String())
+
+	// No temporary files are left behind
+	files, _ := afero.ReadDir(fs, "/sv")
+	assert.Len(t, files, 1)
+
+	err = eso.WriteFileAtomic(fs, "/sv/New.lua", []byte("created"))
+	assert.NoError(t, err)
+
+	data, _ = afero.ReadFile(fs, "/sv/New.lua")
+	assert.Equal(t, "created", string(data))
+}
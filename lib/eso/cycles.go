@@ -0,0 +1,132 @@
+package eso
+
+import (
+	"sort"
+	"strings"
+)
+
+// DependencyCycle is a group of AddOns which depend upon each other in a loop.
+// The game refuses to load any AddOn within the loop.
+type DependencyCycle struct {
+	AddOns   []string // Every AddOn in the loop, sorted alphabetically
+	Optional bool     // True if the loop only exists through optional dependencies
+}
+
+// String returns a description of the loop, e.g. "A, B, C (through optional dependencies)".
+func (C DependencyCycle) String() string {
+	output := strings.Join(C.AddOns, ", ")
+
+	if C.Optional {
+		output += " (through optional dependencies)"
+	}
+
+	return output
+}
+
+// Cycles returns every loop of AddOns which depend upon each other, following both required and optional
+// dependencies. AddOns which only depend upon themselves aren't included, see SelfReferences.
+func (G DependencyGraph) Cycles() []DependencyCycle {
+	var cycles []DependencyCycle
+	var required = map[string]int{}
+
+	// Index of the loop (if any) each AddOn would be in if only required dependencies were followed
+	for i, component := range G.components(func(edge GraphEdge) bool { return edge.Type == RequiredEdge }) {
+		for _, key := range component {
+			required[key] = i
+		}
+	}
+
+	for _, component := range G.components(func(GraphEdge) bool { return true }) {
+		if len(component) < 2 {
+			continue
+		}
+
+		cycle := DependencyCycle{AddOns: component}
+		for _, key := range component[1:] {
+			if required[key] != required[component[0]] {
+				cycle.Optional = true
+				break
+			}
+		}
+
+		cycles = append(cycles, cycle)
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i].AddOns[0] < cycles[j].AddOns[0] })
+
+	return cycles
+}
+
+// SelfReferences returns the edges of AddOns which list themselves as a dependency.
+func (G DependencyGraph) SelfReferences() []GraphEdge {
+	edges := []GraphEdge{}
+
+	for _, edge := range G.Edges {
+		if edge.From == edge.To {
+			edges = append(edges, edge)
+		}
+	}
+
+	return edges
+}
+
+// Returns the strongly connected components of the graph (using Tarjan's algorithm), considering only the edges
+// accepted by the filter. Each component is sorted alphabetically.
+func (G DependencyGraph) components(accept func(GraphEdge) bool) [][]string {
+	var components [][]string
+	var stack []string
+	var index = map[string]int{}
+	var lowlink = map[string]int{}
+	var onStack = map[string]bool{}
+	var adjacent = map[string][]string{}
+
+	for _, edge := range G.Edges {
+		if accept(edge) {
+			adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+		}
+	}
+
+	var connect func(key string)
+	connect = func(key string) {
+		index[key] = len(index)
+		lowlink[key] = index[key]
+		stack = append(stack, key)
+		onStack[key] = true
+
+		for _, next := range adjacent[key] {
+			if _, visited := index[next]; !visited {
+				connect(next)
+				lowlink[key] = min(lowlink[key], lowlink[next])
+			} else if onStack[next] {
+				lowlink[key] = min(lowlink[key], index[next])
+			}
+		}
+
+		if lowlink[key] != index[key] {
+			return
+		}
+
+		var component []string
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+
+			if top == key {
+				break
+			}
+		}
+
+		sort.Strings(component)
+		components = append(components, component)
+	}
+
+	for _, key := range G.Keys() {
+		if _, visited := index[key]; !visited {
+			connect(key)
+		}
+	}
+
+	return components
+}
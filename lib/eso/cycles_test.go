@@ -0,0 +1,56 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cycleGraph() *eso.DependencyGraph {
+	graph := &eso.DependencyGraph{Nodes: map[string]*eso.GraphNode{}}
+
+	for _, key := range []string{"A", "B", "C", "D", "E", "F", "Selfish", "Standalone"} {
+		graph.Nodes[key] = &eso.GraphNode{Key: key}
+	}
+
+	graph.Edges = []eso.GraphEdge{
+		{From: "A", To: "B", Type: eso.RequiredEdge},
+		{From: "B", To: "C", Type: eso.RequiredEdge},
+		{From: "C", To: "A", Type: eso.RequiredEdge},
+		{From: "D", To: "E", Type: eso.OptionalEdge},
+		{From: "E", To: "D", Type: eso.RequiredEdge},
+		{From: "F", To: "A", Type: eso.RequiredEdge},
+		{From: "Selfish", To: "Selfish", Type: eso.RequiredEdge},
+	}
+
+	return graph
+}
+
+func TestDependencyGraph_Cycles(t *testing.T) {
+	cycles := cycleGraph().Cycles()
+	require.Len(t, cycles, 2)
+
+	assert.Equal(t, []string{"A", "B", "C"}, cycles[0].AddOns)
+	assert.False(t, cycles[0].Optional)
+	assert.Equal(t, "A, B, C", cycles[0].String())
+
+	assert.Equal(t, []string{"D", "E"}, cycles[1].AddOns)
+	assert.True(t, cycles[1].Optional)
+	assert.Equal(t, "D, E (through optional dependencies)", cycles[1].String())
+}
+
+func TestDependencyGraph_Cycles_None(t *testing.T) {
+	graph := eso.NewDependencyGraph(graphAddOns(t))
+
+	assert.Empty(t, graph.Cycles())
+}
+
+func TestDependencyGraph_SelfReferences(t *testing.T) {
+	edges := cycleGraph().SelfReferences()
+	require.Len(t, edges, 1)
+
+	assert.Equal(t, "Selfish", edges[0].From)
+	assert.Equal(t, eso.RequiredEdge, edges[0].Type)
+}
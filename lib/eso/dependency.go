@@ -0,0 +1,97 @@
+package eso
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dependency represents a single entry of a DependsOn or OptionalDependsOn directive.
+// Entries take the form "Name" or "Name>=AddOnVersion", e.g. "LibGPS>=71".
+type Dependency struct {
+	Name       string // Name (key) of the AddOn depended upon
+	MinVersion int    // Minimum AddOnVersion required, only meaningful when HasConstraint() is true
+	constraint bool
+}
+
+// ParseDependency parses a single DependsOn entry into a Dependency.
+// It returns an error if the version constraint is malformed, along with as much of the Dependency as could be parsed.
+func ParseDependency(input string) (Dependency, error) {
+	input = strings.TrimSpace(input)
+	name, version, found := strings.Cut(input, ">=")
+
+	if name == "" || strings.IndexAny(name, "<>=") == 0 {
+		return Dependency{}, fmt.Errorf("%q is missing a dependency name", input)
+	}
+
+	if i := strings.IndexAny(name, "<>="); i >= 0 {
+		return Dependency{Name: name[:i]}, fmt.Errorf("%q has an unsupported version constraint (only >= is allowed)", input)
+	}
+
+	dependency := Dependency{Name: name}
+
+	if !found {
+		return dependency, nil
+	}
+
+	number, err := strconv.Atoi(version)
+	if err != nil || number < 0 {
+		return dependency, fmt.Errorf("%q has a malformed version constraint, expected a whole number after >=", input)
+	}
+
+	dependency.MinVersion = number
+	dependency.constraint = true
+
+	return dependency, nil
+}
+
+// String returns the Dependency in manifest format, e.g. "LibGPS>=71".
+func (D Dependency) String() string {
+	if !D.constraint {
+		return D.Name
+	}
+
+	return fmt.Sprintf("%s>=%d", D.Name, D.MinVersion)
+}
+
+// Constraint returns the version constraint in manifest format (e.g. ">=71"), or an empty string if there is none.
+func (D Dependency) Constraint() string {
+	if !D.constraint {
+		return ""
+	}
+
+	return fmt.Sprintf(">=%d", D.MinVersion)
+}
+
+// HasConstraint returns true if the Dependency requires a minimum AddOnVersion.
+func (D Dependency) HasConstraint() bool {
+	return D.constraint
+}
+
+// SatisfiedBy returns true if the given AddOn meets the Dependency's version constraint.
+// An AddOn without a valid AddOnVersion is treated as version 0, as the game does.
+func (D Dependency) SatisfiedBy(addon AddOn) bool {
+	if !D.constraint {
+		return true
+	}
+
+	version, _ := addon.AddOnVersionNumber()
+
+	return version >= D.MinVersion
+}
+
+// parseDependencies parses a list of DependsOn entries, skipping any that don't have a name.
+func parseDependencies(entries []string) []Dependency {
+	dependencies := []Dependency{}
+
+	for _, entry := range entries {
+		dependency, _ := ParseDependency(entry)
+		if dependency.Name == "" {
+			continue
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies
+}
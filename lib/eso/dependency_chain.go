@@ -0,0 +1,126 @@
+package eso
+
+import (
+	"sort"
+	"strings"
+)
+
+// DependencyChain is a path through the DependencyGraph, from a top-level AddOn (one which nothing else
+// depends upon) down to the AddOn being traced. Each edge depends upon the AddOn of the edge following it.
+type DependencyChain []GraphEdge
+
+// Root returns the key of the top-level AddOn at the start of the chain.
+func (C DependencyChain) Root() string {
+	if len(C) == 0 {
+		return ""
+	}
+
+	return C[0].From
+}
+
+// Required returns true if every link in the chain is a required dependency,
+// meaning the top-level AddOn won't load without the traced AddOn.
+func (C DependencyChain) Required() bool {
+	for _, edge := range C {
+		if edge.Type == OptionalEdge {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the chain formatted as "Root -> Dependency -> AddOn", marking optional links with "-(optional)->".
+func (C DependencyChain) String() string {
+	if len(C) == 0 {
+		return ""
+	}
+
+	var output strings.Builder
+
+	output.WriteString(C[0].From)
+
+	for _, edge := range C {
+		if edge.Type == OptionalEdge {
+			output.WriteString(" -(optional)-> ")
+		} else {
+			output.WriteString(" -> ")
+		}
+
+		output.WriteString(edge.To)
+	}
+
+	return output.String()
+}
+
+// ChainsTo returns every chain of dependents leading to the given AddOn, each starting at a top-level AddOn.
+// Required chains are listed first, then sorted alphabetically. Loops in the graph are followed only once.
+//
+// Widely shared libraries can be reached by a great many chains, as the number of chains doubles with each AddOn
+// depending on two others which lead to the same library. If limit is above zero, no more than limit chains are
+// returned, and truncated is true if there were more. Required chains are collected first, and any room left within the
+// limit is filled with optional ones.
+func (G DependencyGraph) ChainsTo(key string, limit int) (chains []DependencyChain, truncated bool) {
+	truncated = !G.walkDependents(key, map[string]bool{key: true}, nil, &chains, limit, true)
+
+	if !truncated {
+		truncated = !G.walkDependents(key, map[string]bool{key: true}, nil, &chains, limit, false)
+	}
+
+	sort.SliceStable(chains, func(i, j int) bool {
+		if chains[i].Required() != chains[j].Required() {
+			return chains[i].Required()
+		}
+
+		return chains[i].String() < chains[j].String()
+	})
+
+	return chains, truncated
+}
+
+// Walks up the graph from key, adding a chain each time an AddOn with no further dependents is reached.
+// The path is built from the traced AddOn upwards, then reversed into a chain. With required, only required dependents
+// are walked, finding the required chains; otherwise every dependent is walked, and only the chains with an optional
+// link are added. Returns false, and stops walking, once a chain is found beyond the limit.
+func (G DependencyGraph) walkDependents(key string, visiting map[string]bool, path []GraphEdge, chains *[]DependencyChain, limit int, required bool) bool {
+	var extended bool
+
+	for _, edge := range G.DependentsOf(key) {
+		if visiting[edge.From] {
+			continue
+		}
+
+		extended = true
+
+		if required && edge.Type == OptionalEdge {
+			continue
+		}
+
+		visiting[edge.From] = true
+		complete := G.walkDependents(edge.From, visiting, append(path, edge), chains, limit, required)
+		delete(visiting, edge.From)
+
+		if !complete {
+			return false
+		}
+	}
+
+	if !extended && len(path) > 0 {
+		chain := make(DependencyChain, len(path))
+		for i, edge := range path {
+			chain[len(path)-1-i] = edge
+		}
+
+		if chain.Required() != required {
+			return true
+		}
+
+		if limit > 0 && len(*chains) >= limit {
+			return false
+		}
+
+		*chains = append(*chains, chain)
+	}
+
+	return true
+}
@@ -0,0 +1,135 @@
+package eso_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chainGraph() *eso.DependencyGraph {
+	return &eso.DependencyGraph{
+		Nodes: map[string]*eso.GraphNode{
+			"Top":    {Key: "Top"},
+			"MyMap":  {Key: "MyMap"},
+			"LibMid": {Key: "LibMid"},
+			"LibGPS": {Key: "LibGPS"},
+			"LoopA":  {Key: "LoopA"},
+			"LoopB":  {Key: "LoopB"},
+		},
+		Edges: []eso.GraphEdge{
+			{From: "Top", To: "LibMid", Type: eso.RequiredEdge},
+			{From: "Top", To: "LibGPS", Type: eso.OptionalEdge},
+			{From: "LibMid", To: "LibGPS", Type: eso.RequiredEdge},
+			{From: "MyMap", To: "LibGPS", Type: eso.RequiredEdge},
+			{From: "LoopA", To: "LoopB", Type: eso.RequiredEdge},
+			{From: "LoopB", To: "LoopA", Type: eso.RequiredEdge},
+		},
+	}
+}
+
+func TestDependencyGraph_ChainsTo(t *testing.T) {
+	chains, truncated := chainGraph().ChainsTo("LibGPS", 0)
+	assert.False(t, truncated)
+	require.Len(t, chains, 3)
+
+	assert.Equal(t, "MyMap -> LibGPS", chains[0].String())
+	assert.True(t, chains[0].Required())
+	assert.Equal(t, "MyMap", chains[0].Root())
+
+	assert.Equal(t, "Top -> LibMid -> LibGPS", chains[1].String())
+	assert.True(t, chains[1].Required())
+	assert.Equal(t, "Top", chains[1].Root())
+
+	assert.Equal(t, "Top -(optional)-> LibGPS", chains[2].String())
+	assert.False(t, chains[2].Required())
+}
+
+func TestDependencyGraph_ChainsTo_TopLevel(t *testing.T) {
+	chains, truncated := chainGraph().ChainsTo("Top", 0)
+	assert.Empty(t, chains)
+	assert.False(t, truncated)
+}
+
+func TestDependencyGraph_ChainsTo_Loop(t *testing.T) {
+	chains, _ := chainGraph().ChainsTo("LoopA", 0)
+	require.Len(t, chains, 1)
+
+	assert.Equal(t, "LoopB -> LoopA", chains[0].String())
+}
+
+func TestDependencyGraph_ChainsTo_Limit(t *testing.T) {
+	chains, truncated := chainGraph().ChainsTo("LibGPS", 3)
+	assert.Len(t, chains, 3)
+	assert.False(t, truncated)
+
+	chains, truncated = chainGraph().ChainsTo("LibGPS", 2)
+	assert.True(t, truncated)
+	require.Len(t, chains, 2)
+
+	// Required chains are collected first, so the optional chain is the one left out
+	assert.True(t, chains[0].Required())
+	assert.True(t, chains[1].Required())
+}
+
+func TestDependencyGraph_ChainsTo_LimitPrefersRequired(t *testing.T) {
+	var graph = &eso.DependencyGraph{
+		Nodes: map[string]*eso.GraphNode{
+			"Lib":      {Key: "Lib"},
+			"Helper":   {Key: "Helper"},
+			"Optional": {Key: "Optional"},
+			"Required": {Key: "Required"},
+		},
+		Edges: []eso.GraphEdge{
+			{From: "Helper", To: "Lib", Type: eso.RequiredEdge},
+			{From: "Optional", To: "Helper", Type: eso.OptionalEdge},
+			{From: "Required", To: "Lib", Type: eso.RequiredEdge},
+		},
+	}
+
+	// The optional link is further up the chain, past the first dependent walked
+	chains, truncated := graph.ChainsTo("Lib", 1)
+	assert.True(t, truncated)
+	require.Len(t, chains, 1)
+	assert.Equal(t, "Required -> Lib", chains[0].String())
+
+	chains, truncated = graph.ChainsTo("Lib", 2)
+	assert.False(t, truncated)
+	require.Len(t, chains, 2)
+	assert.Equal(t, "Optional -(optional)-> Helper -> Lib", chains[1].String())
+}
+
+func TestDependencyGraph_ChainsTo_Diamonds(t *testing.T) {
+	var graph = &eso.DependencyGraph{Nodes: map[string]*eso.GraphNode{"Lib": {Key: "Lib"}}}
+	var below = []string{"Lib"}
+
+	// 40 layers of two AddOns, each depending on both AddOns of the layer below, lead to 2^40 chains
+	for layer := 0; layer < 40; layer++ {
+		var keys = []string{fmt.Sprintf("A%d", layer), fmt.Sprintf("B%d", layer)}
+
+		for _, key := range keys {
+			graph.Nodes[key] = &eso.GraphNode{Key: key}
+
+			for _, dependency := range below {
+				graph.Edges = append(graph.Edges, eso.GraphEdge{From: key, To: dependency, Type: eso.RequiredEdge})
+			}
+		}
+
+		below = keys
+	}
+
+	chains, truncated := graph.ChainsTo("Lib", 100)
+	assert.True(t, truncated)
+	require.Len(t, chains, 100)
+	assert.Len(t, chains[0], 40)
+}
+
+func TestDependencyChain_Empty(t *testing.T) {
+	var chain eso.DependencyChain
+
+	assert.Equal(t, "", chain.String())
+	assert.Equal(t, "", chain.Root())
+	assert.True(t, chain.Required())
+}
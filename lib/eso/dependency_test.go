@@ -0,0 +1,101 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDependency(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		expectName string
+		expectMin  int
+		constraint bool
+		expectErr  bool
+	}{
+		{name: "plain name", input: "LibGPS", expectName: "LibGPS"},
+		{name: "with constraint", input: "LibGPS>=71", expectName: "LibGPS", expectMin: 71, constraint: true},
+		{name: "with surrounding whitespace", input: " LibGPS>=71\r\n", expectName: "LibGPS", expectMin: 71, constraint: true},
+		{name: "name containing hyphens and dots", input: "LibAddonMenu-2.0>=35", expectName: "LibAddonMenu-2.0", expectMin: 35, constraint: true},
+		{name: "non-numeric version", input: "LibGPS>=v71", expectName: "LibGPS", expectErr: true},
+		{name: "empty version", input: "LibGPS>=", expectName: "LibGPS", expectErr: true},
+		{name: "unsupported operator", input: "LibGPS>71", expectName: "LibGPS", expectErr: true},
+		{name: "equality operator", input: "LibGPS==71", expectName: "LibGPS", expectErr: true},
+		{name: "missing name", input: ">=71", expectErr: true},
+		{name: "empty", input: "", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			dependency, err := eso.ParseDependency(tt.input)
+
+			if tt.expectErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+
+			assert.Equal(tt.expectName, dependency.Name)
+			assert.Equal(tt.expectMin, dependency.MinVersion)
+			assert.Equal(tt.constraint, dependency.HasConstraint())
+		})
+	}
+}
+
+func TestDependency_String(t *testing.T) {
+	withConstraint, _ := eso.ParseDependency("LibGPS>=71")
+	withoutConstraint, _ := eso.ParseDependency("LibGPS")
+
+	assert.Equal(t, "LibGPS>=71", withConstraint.String())
+	assert.Equal(t, ">=71", withConstraint.Constraint())
+	assert.Equal(t, "LibGPS", withoutConstraint.String())
+	assert.Equal(t, "", withoutConstraint.Constraint())
+}
+
+func TestDependency_SatisfiedBy(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependency   string
+		addonVersion string
+		expect       bool
+	}{
+		{name: "no constraint", dependency: "LibGPS", addonVersion: "", expect: true},
+		{name: "newer version", dependency: "LibGPS>=71", addonVersion: "72", expect: true},
+		{name: "exact version", dependency: "LibGPS>=71", addonVersion: "71", expect: true},
+		{name: "older version", dependency: "LibGPS>=71", addonVersion: "70", expect: false},
+		{name: "missing version", dependency: "LibGPS>=71", addonVersion: "", expect: false},
+		{name: "invalid version", dependency: "LibGPS>=71", addonVersion: "1.2.3", expect: false},
+		{name: "zero constraint with missing version", dependency: "LibGPS>=0", addonVersion: "", expect: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dependency, _ := eso.ParseDependency(tt.dependency)
+			addon, _ := eso.NewAddOn("LibGPS")
+			addon.AddOnVersion = tt.addonVersion
+
+			assert.Equal(t, tt.expect, dependency.SatisfiedBy(addon))
+		})
+	}
+}
+
+func TestAddOn_Dependencies(t *testing.T) {
+	addon, _ := eso.NewAddOn("MyMap")
+	addon.DependsOn = []string{"LibGPS>=71", "", "LibMapPing"}
+	addon.OptionalDependsOn = []string{"LibDebugLogger>=263"}
+
+	dependencies := addon.Dependencies()
+	optionalDependencies := addon.OptionalDependencies()
+
+	assert.Len(t, dependencies, 2)
+	assert.Equal(t, "LibGPS", dependencies[0].Name)
+	assert.Equal(t, 71, dependencies[0].MinVersion)
+	assert.Equal(t, "LibMapPing", dependencies[1].Name)
+
+	assert.Len(t, optionalDependencies, 1)
+	assert.Equal(t, 263, optionalDependencies[0].MinVersion)
+}
@@ -0,0 +1,112 @@
+package eso
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DependencyTreeNode is an AddOn within the transitive dependency tree of another AddOn.
+type DependencyTreeNode struct {
+	Node     *GraphNode
+	Edge     *GraphEdge // The dependency leading to this AddOn, or nil for the root of the tree
+	Shared   bool       // True if more than one installed AddOn depends upon this AddOn
+	Circular bool       // True if this AddOn is already an ancestor within the tree, so it isn't expanded again
+	Children []*DependencyTreeNode
+}
+
+// DependencyTree returns the full transitive dependency tree of each of the given AddOns.
+// Optional dependencies are only followed if includeOptional is true.
+func (G DependencyGraph) DependencyTree(includeOptional bool, roots ...string) ([]*DependencyTreeNode, error) {
+	var trees []*DependencyTreeNode
+
+	for _, root := range roots {
+		node, exists := G.Find(root)
+		if !exists {
+			return nil, fmt.Errorf("AddOn %q is not installed", root)
+		}
+
+		trees = append(trees, G.dependencyTree(includeOptional, node, nil, map[string]bool{}))
+	}
+
+	return trees, nil
+}
+
+func (G DependencyGraph) dependencyTree(includeOptional bool, node *GraphNode, edge *GraphEdge, ancestors map[string]bool) *DependencyTreeNode {
+	tree := &DependencyTreeNode{Node: node, Edge: edge, Shared: node.Dependents > 1, Circular: ancestors[node.Key]}
+
+	if tree.Circular {
+		return tree
+	}
+
+	ancestors[node.Key] = true
+	defer delete(ancestors, node.Key)
+
+	for _, dependency := range G.DependenciesOf(node.Key) {
+		if dependency.Type == OptionalEdge && !includeOptional {
+			continue
+		}
+
+		tree.Children = append(tree.Children, G.dependencyTree(includeOptional, G.Nodes[dependency.To], &dependency, ancestors))
+	}
+
+	return tree
+}
+
+// TopLevel returns the keys of the installed AddOns which no other AddOn depends upon, sorted alphabetically.
+func (G DependencyGraph) TopLevel() []string {
+	keys := []string{}
+
+	for _, key := range G.Keys() {
+		node := G.Nodes[key]
+
+		if !node.Missing && len(G.dependentsExcludingSelf(key)) == 0 {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (G DependencyGraph) dependentsExcludingSelf(key string) []GraphEdge {
+	edges := []GraphEdge{}
+
+	for _, edge := range G.DependentsOf(key) {
+		if edge.From != key {
+			edges = append(edges, edge)
+		}
+	}
+
+	return edges
+}
+
+// Closure returns the minimum set of top-level folders (relative to the AddOns directory) needed to run the given
+// AddOns, by following their required dependencies. AddOns embedded within another AddOn are provided by the folder
+// they're embedded in. The keys of any required dependencies which aren't installed are returned as well.
+func (A AddOns) Closure(names ...string) (folders []string, missing []string, err error) {
+	graph, err := NewDependencyGraph(A).Subgraph(false, names...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unique := map[string]bool{}
+
+	for _, key := range graph.Keys() {
+		if graph.Nodes[key].Missing {
+			missing = append(missing, key)
+			continue
+		}
+
+		addon := A[key]
+		folder := strings.Split(strings.TrimLeft(filepath.ToSlash(addon.Dir()), "/"), "/")[0]
+		unique[folder] = true
+	}
+
+	for folder := range unique {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+
+	return folders, missing, nil
+}
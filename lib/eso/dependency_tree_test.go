@@ -0,0 +1,78 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyGraph_DependencyTree(t *testing.T) {
+	graph := eso.NewDependencyGraph(graphAddOns(t))
+
+	trees, err := graph.DependencyTree(true, "MyMap")
+	require.NoError(t, err)
+	require.Len(t, trees, 1)
+
+	root := trees[0]
+	assert.Equal(t, "MyMap", root.Node.Key)
+	assert.Nil(t, root.Edge)
+	require.Len(t, root.Children, 4)
+
+	gps := root.Children[0]
+	assert.Equal(t, "LibGPS", gps.Node.Key)
+	assert.Equal(t, ">=71", gps.Edge.Constraint)
+	assert.True(t, gps.Edge.Outdated)
+	assert.True(t, gps.Shared)
+
+	assert.True(t, root.Children[1].Node.Missing)
+	assert.Equal(t, eso.OptionalEdge, root.Children[2].Edge.Type)
+
+	trees, err = graph.DependencyTree(false, "MyMap")
+	require.NoError(t, err)
+	assert.Len(t, trees[0].Children, 2)
+
+	_, err = graph.DependencyTree(false, "Unknown")
+	assert.Error(t, err)
+}
+
+func TestDependencyGraph_DependencyTree_Circular(t *testing.T) {
+	trees, err := cycleGraph().DependencyTree(false, "A")
+	require.NoError(t, err)
+
+	b := trees[0].Children[0]
+	c := b.Children[0]
+	a := c.Children[0]
+
+	assert.Equal(t, "A", a.Node.Key)
+	assert.True(t, a.Circular)
+	assert.Empty(t, a.Children)
+	assert.False(t, b.Circular)
+}
+
+func TestDependencyGraph_TopLevel(t *testing.T) {
+	assert.Equal(t, []string{"MyMap", "Other"}, eso.NewDependencyGraph(graphAddOns(t)).TopLevel())
+	assert.Equal(t, []string{"F", "Selfish", "Standalone"}, cycleGraph().TopLevel())
+}
+
+func TestAddOns_Closure(t *testing.T) {
+	addons, errs := eso.GetAddOns(addOnsFs(t, map[string]string{
+		"AddOns/MyAddon/MyAddon.txt":              "## Title: My Addon\n## DependsOn: LibEmbedded LibTop LibMissing\n## OptionalDependsOn: LibOptional\n",
+		"AddOns/MyAddon/Libs/LibOwn/LibOwn.txt":   "## Title: LibOwn\n",
+		"AddOns/Host/Host.txt":                    "## Title: Host\n",
+		"AddOns/Host/LibEmbedded/LibEmbedded.txt": "## Title: LibEmbedded\n## DependsOn: LibOwn\n",
+		"AddOns/LibTop/LibTop.txt":                "## Title: LibTop\n",
+		"AddOns/LibOptional/LibOptional.txt":      "## Title: LibOptional\n",
+	}))
+	require.Empty(t, errs)
+
+	folders, missing, err := addons.Closure("MyAddon")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Host", "LibTop", "MyAddon"}, folders)
+	assert.Equal(t, []string{"LibMissing"}, missing)
+
+	_, _, err = addons.Closure("Unknown")
+	assert.Error(t, err)
+}
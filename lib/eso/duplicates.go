@@ -0,0 +1,90 @@
+package eso
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// DuplicateCopy describes one installed copy of an AddOn that's installed more than once.
+type DuplicateCopy struct {
+	Dir          string `json:"dir"`          // Directory of the copy, relative to the AddOns directory
+	AddOnVersion string `json:"addOnVersion"` // AddOnVersion declared by the copy
+	Parent       string `json:"parent"`       // Key of the AddOn this copy is bundled with, if any
+	Loaded       bool   `json:"loaded"`       // True if this is the copy the game will load
+	Removable    bool   `json:"removable"`    // True if the copy can safely be removed
+}
+
+// DuplicateAddOn describes an AddOn with more than one installed copy.
+type DuplicateAddOn struct {
+	Key    string          `json:"key"`
+	Copies []DuplicateCopy `json:"copies"` // Every installed copy, starting with the one the game will load
+}
+
+// Loaded returns the copy the game will load.
+func (D DuplicateAddOn) Loaded() DuplicateCopy {
+	return D.Copies[0]
+}
+
+// Removable returns the copies which are safe to remove: those the game ignores, which aren't bundled inside another
+// AddOn (removing those would modify the other AddOn), and which don't contain any AddOn the game loads.
+func (D DuplicateAddOn) Removable() []DuplicateCopy {
+	removable := []DuplicateCopy{}
+
+	for _, copy := range D.Copies {
+		if copy.Removable {
+			removable = append(removable, copy)
+		}
+	}
+
+	return removable
+}
+
+// Duplicates returns every AddOn with more than one installed copy, sorted by key.
+func (A AddOns) Duplicates() []DuplicateAddOn {
+	duplicates := []DuplicateAddOn{}
+
+	for _, key := range A.Keys() {
+		addon := A[key]
+
+		if len(addon.Copies()) == 0 {
+			continue
+		}
+
+		duplicate := DuplicateAddOn{Key: key}
+
+		for i, instance := range addon.Instances() {
+			duplicate.Copies = append(duplicate.Copies, DuplicateCopy{
+				Dir:          instance.Dir(),
+				AddOnVersion: instance.AddOnVersion,
+				Parent:       instance.Parent(),
+				Loaded:       i == 0,
+				Removable:    i != 0 && instance.Parent() == "" && !A.loadsWithin(instance.Dir()),
+			})
+		}
+
+		duplicates = append(duplicates, duplicate)
+	}
+
+	return duplicates
+}
+
+// Returns true if the game loads any AddOn from within dir, such as a library only bundled with an unused copy
+func (A AddOns) loadsWithin(dir string) bool {
+	for _, addon := range A {
+		if isWithinDir(addon.meta.dir, dir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DuplicatesToJson returns the duplicates report in JSON format.
+func DuplicatesToJson(duplicates []DuplicateAddOn) ([]byte, error) {
+	output, err := json.Marshal(duplicates)
+	if err != nil {
+		return []byte{}, fmt.Errorf("error marshalling JSON: %w", err)
+	}
+
+	return output, nil
+}
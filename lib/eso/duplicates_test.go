@@ -0,0 +1,77 @@
+package eso_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddOns_Duplicates(t *testing.T) {
+	addons, errs := eso.GetAddOns(nestedAddOnsFs(t))
+	require.Empty(t, errs)
+
+	duplicates := addons.Duplicates()
+	require.Len(t, duplicates, 1)
+
+	duplicate := duplicates[0]
+	assert.Equal(t, "LibAddonMenu-2.0", duplicate.Key)
+	require.Len(t, duplicate.Copies, 3)
+
+	loaded := duplicate.Loaded()
+	assert.True(t, loaded.Loaded)
+	assert.Equal(t, "35", loaded.AddOnVersion)
+	assert.Equal(t, "MyAddon", loaded.Parent)
+	assert.False(t, loaded.Removable)
+
+	removable := duplicate.Removable()
+	require.Len(t, removable, 1)
+	assert.Equal(t, filepath.FromSlash("/LibAddonMenu-2.0"), removable[0].Dir)
+	assert.Equal(t, "30", removable[0].AddOnVersion)
+}
+
+func TestAddOns_Duplicates_ContainsLoadedAddOn(t *testing.T) {
+	addons, errs := eso.GetAddOns(addOnsFs(t, map[string]string{
+		"AddOns/LibA/LibA.txt":      "## Title: LibA\n## AddOnVersion: 1\n",
+		"AddOns/LibA/LibX/LibX.txt": "## Title: LibX\n",
+		"AddOns/Host/Host.txt":      "## Title: Host\n",
+		"AddOns/Host/LibA/LibA.txt": "## Title: LibA\n## AddOnVersion: 10\n",
+		"AddOns/User/User.txt":      "## Title: User\n## DependsOn: LibX\n",
+	}))
+	require.Empty(t, errs)
+
+	duplicates := addons.Duplicates()
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, "LibA", duplicates[0].Key)
+	assert.Equal(t, "10", duplicates[0].Loaded().AddOnVersion)
+
+	// The unused top-level copy holds the only copy of LibX, which User requires
+	require.Len(t, duplicates[0].Copies, 2)
+	assert.Equal(t, filepath.FromSlash("/LibA"), duplicates[0].Copies[1].Dir)
+	assert.False(t, duplicates[0].Copies[1].Removable)
+	assert.Empty(t, duplicates[0].Removable())
+}
+
+func TestAddOns_Duplicates_None(t *testing.T) {
+	addons := eso.AddOns{"addon1": eso.AddOn{Title: "Addon One"}}
+
+	assert.Empty(t, addons.Duplicates())
+}
+
+func TestDuplicatesToJson(t *testing.T) {
+	duplicates := []eso.DuplicateAddOn{{
+		Key: "LibGPS",
+		Copies: []eso.DuplicateCopy{
+			{Dir: "/LibGPS", AddOnVersion: "72", Loaded: true},
+			{Dir: "/Other/LibGPS", AddOnVersion: "60", Parent: "Other"},
+		},
+	}}
+
+	output, err := eso.DuplicatesToJson(duplicates)
+	require.NoError(t, err)
+
+	expected := `[{"key":"LibGPS","copies":[{"dir":"/LibGPS","addOnVersion":"72","parent":"","loaded":true,"removable":false},{"dir":"/Other/LibGPS","addOnVersion":"60","parent":"Other","loaded":false,"removable":false}]}]`
+	assert.JSONEq(t, expected, string(output))
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/iancoleman/strcase"
@@ -32,6 +33,10 @@ type addonMeta struct {
 	dir        string
 	dependency bool
 	library    bool
+	manifest   *Manifest
+	shadowed   string
+	parent     string
+	copies     []AddOn
 	errs       []error
 }
 
@@ -66,6 +71,53 @@ func NewAddOn(key string) (AddOn, error) {
 	return AddOn{meta: addonMeta{key: key}}, nil
 }
 
+// NewAddOnFromManifest creates a new instance of AddOn with the provided key,
+// populating its fields from the directives found in the given manifest.
+// Repeated list directives (e.g. multiple "## DependsOn:" lines) are accumulated.
+func NewAddOnFromManifest(key string, manifest *Manifest) (AddOn, error) {
+	addon, err := NewAddOn(key)
+	if err != nil {
+		return addon, err
+	}
+
+	addon.meta.manifest = manifest
+
+	for _, line := range manifest.Directives() {
+		value := cleanString(line.Value)
+
+		switch line.Directive {
+		case "Title":
+			addon.Title = value
+		case "Description":
+			addon.Description = value
+		case "Author":
+			addon.Author = value
+		case "Contributors":
+			addon.Contributors = value
+		case "Version":
+			addon.Version = strings.TrimPrefix(value, "v")
+		case "AddOnVersion", "AddonVersion":
+			addon.AddOnVersion = value
+		case "APIVersion":
+			addon.APIVersion = value
+		case "SavedVariables":
+			addon.SavedVariables = append(addon.SavedVariables, strings.Fields(value)...)
+		case "DependsOn":
+			addon.DependsOn = append(addon.DependsOn, strings.Fields(value)...)
+		case "OptionalDependsOn":
+			addon.OptionalDependsOn = append(addon.OptionalDependsOn, strings.Fields(value)...)
+		case "IsLibrary":
+			addon.SetLibrary(value == "true")
+		default:
+			if viper.GetInt("verbosity") >= 3 {
+				fmt.Println(fmt.Errorf("unknown type: %s with value: %s", line.Directive, value))
+			}
+		}
+	}
+
+	return addon, nil
+}
+
 // String returns a string representation of the AddOn.
 // It includes all fields of the AddOn, separated by commas.
 func (A AddOn) String() string {
@@ -164,6 +216,73 @@ func (A *AddOn) Dir() string {
 	return A.meta.dir
 }
 
+// Manifest returns the manifest the AddOn was built from, or nil if it wasn't built from one.
+func (A AddOn) Manifest() *Manifest {
+	return A.meta.manifest
+}
+
+// AddOnVersionNumber returns the AddOnVersion as an integer, and whether it could be parsed.
+// The game treats a missing or invalid AddOnVersion as 0.
+func (A AddOn) AddOnVersionNumber() (int, bool) {
+	version, err := strconv.Atoi(A.AddOnVersion)
+	if err != nil {
+		return 0, false
+	}
+
+	return version, true
+}
+
+// APIVersions returns the API versions declared by the AddOn's APIVersion directive, in the order declared.
+// Any values which aren't whole numbers are ignored.
+func (A AddOn) APIVersions() []int {
+	versions := []int{}
+
+	for _, field := range strings.Fields(A.APIVersion) {
+		if version, err := strconv.Atoi(field); err == nil {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions
+}
+
+// LatestAPIVersion returns the newest API version declared by the AddOn, or 0 if none are declared.
+func (A AddOn) LatestAPIVersion() int {
+	var latest int
+
+	for _, version := range A.APIVersions() {
+		latest = max(latest, version)
+	}
+
+	return latest
+}
+
+// IsOutOfDate returns true if the AddOn doesn't declare support for the given (current) game API version,
+// which is what the game uses to flag an AddOn as "Out of date".
+func (A AddOn) IsOutOfDate(currentAPIVersion int) bool {
+	return A.LatestAPIVersion() < currentAPIVersion
+}
+
+// Dependencies returns the parsed DependsOn entries of the AddOn.
+func (A AddOn) Dependencies() []Dependency {
+	return parseDependencies(A.DependsOn)
+}
+
+// OptionalDependencies returns the parsed OptionalDependsOn entries of the AddOn.
+func (A AddOn) OptionalDependencies() []Dependency {
+	return parseDependencies(A.OptionalDependsOn)
+}
+
+// SetShadowed records the name of a lower precedence manifest that the game ignores for this AddOn.
+func (A *AddOn) SetShadowed(name string) {
+	A.meta.shadowed = name
+}
+
+// Shadowed returns the name of the manifest ignored in favor of this AddOn's manifest, if any.
+func (A AddOn) Shadowed() string {
+	return A.meta.shadowed
+}
+
 // SetDependency sets the dependency status of the AddOn.
 func (A *AddOn) SetDependency(value bool) {
 	A.meta.dependency = value
@@ -185,10 +304,48 @@ func (A *AddOn) IsLibrary() bool {
 }
 
 // IsSubmodule returns true if the AddOn is a submodule, and false otherwise.
-// It checks if the directory of the AddOn contains more than one path element.
+// An AddOn is a submodule if it's embedded within another AddOn's directory (see Parent),
+// or if its directory contains more than one path element.
 func (A AddOn) IsSubmodule() bool {
 	files, _ := filepath.Split(A.meta.dir)
-	return len(files) > 1
+	return A.meta.parent != "" || len(files) > 1
+}
+
+// Parent returns the key of the AddOn whose directory this AddOn is embedded in,
+// or an empty string if it's installed directly within the AddOns directory.
+func (A AddOn) Parent() string {
+	return A.meta.parent
+}
+
+// Depth returns the number of directories between the AddOns directory and the AddOn, where 1 is top-level.
+func (A AddOn) Depth() int {
+	return len(strings.FieldsFunc(A.meta.dir, func(r rune) bool { return r == '/' || r == '\\' }))
+}
+
+// Copies returns every other installed copy of this AddOn, which the game will not load
+// because this copy has a higher AddOnVersion (or is less deeply nested).
+func (A AddOn) Copies() []AddOn {
+	return A.meta.copies
+}
+
+// Instances returns this AddOn followed by every other installed copy of it.
+func (A AddOn) Instances() []AddOn {
+	return append([]AddOn{A.withoutCopies()}, A.meta.copies...)
+}
+
+func (A AddOn) hasTopLevelCopy() bool {
+	for _, copy := range A.meta.copies {
+		if !copy.IsSubmodule() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (A AddOn) withoutCopies() AddOn {
+	A.meta.copies = nil
+	return A
 }
 
 // AddError appends an error to the list of errors of the AddOn.
@@ -313,6 +470,18 @@ func (A AddOns) Keys() []string {
 	return keys
 }
 
+// LatestAPIVersion returns the highest API version declared by any of the AddOns.
+// This is a good approximation of the current game API version when one hasn't been configured.
+func (A AddOns) LatestAPIVersion() int {
+	var latest int
+
+	for _, addon := range A {
+		latest = max(latest, addon.LatestAPIVersion())
+	}
+
+	return latest
+}
+
 // Print prints the global AddOns map in the specified format.
 // It checks the format and calls the corresponding function to print the AddOns.
 // If the format is "json", it marshals the AddOns into JSON format and prints the byte slice.
@@ -333,8 +502,8 @@ func (A AddOns) Print(format string) string {
 	for _, key := range A.Keys() {
 		addon := A[key]
 
-		// Don't print out submodules
-		if addon.IsSubmodule() {
+		// Don't print out submodules, unless a copy is also installed at the top level
+		if addon.IsSubmodule() && !addon.hasTopLevelCopy() {
 			continue
 		}
 
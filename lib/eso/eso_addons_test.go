@@ -211,3 +211,47 @@ func TestStripESOColorCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIVersions(t *testing.T) {
+	tests := []struct {
+		name         string
+		apiVersion   string
+		expect       []int
+		expectLatest int
+	}{
+		{name: "single version", apiVersion: "101042", expect: []int{101042}, expectLatest: 101042},
+		{name: "multiple versions", apiVersion: "101042 101041", expect: []int{101042, 101041}, expectLatest: 101042},
+		{name: "extra whitespace", apiVersion: " 101041  101042 ", expect: []int{101041, 101042}, expectLatest: 101042},
+		{name: "invalid values are ignored", apiVersion: "101041 latest", expect: []int{101041}, expectLatest: 101041},
+		{name: "empty", apiVersion: "", expect: []int{}, expectLatest: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addon := eso.AddOn{APIVersion: tt.apiVersion}
+
+			assert.Equal(t, tt.expect, addon.APIVersions())
+			assert.Equal(t, tt.expectLatest, addon.LatestAPIVersion())
+		})
+	}
+}
+
+func TestIsOutOfDate(t *testing.T) {
+	addon := eso.AddOn{APIVersion: "101040 101041"}
+
+	assert.False(t, addon.IsOutOfDate(101040))
+	assert.False(t, addon.IsOutOfDate(101041))
+	assert.True(t, addon.IsOutOfDate(101042))
+	assert.True(t, eso.AddOn{}.IsOutOfDate(101042))
+}
+
+func TestAddOns_LatestAPIVersion(t *testing.T) {
+	addons := eso.AddOns{
+		"addon1": eso.AddOn{APIVersion: "101041"},
+		"addon2": eso.AddOn{APIVersion: "101042 101041"},
+		"addon3": eso.AddOn{},
+	}
+
+	assert.Equal(t, 101042, addons.LatestAPIVersion())
+	assert.Equal(t, 0, eso.AddOns{}.LatestAPIVersion())
+}
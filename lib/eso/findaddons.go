@@ -26,6 +26,15 @@ func FindAddOns(AppFs afero.Fs) ([]AddOnDefinition, error) {
 		return nil, fmt.Errorf("error occurred while walking %q: %w", addonsPath, err)
 	}
 
+	addons = resolveManifestPrecedence(addons)
+
+	// Commands which check AddOns report these themselves (see "esotools check addons"), so only trace them here
+	for _, addon := range addons {
+		if addon.Shadowed != "" && verbosity >= 2 {
+			fmt.Printf("Found both %s and %s in %s, using %s\n", addon.Name, addon.Shadowed, addon.Dir, addon.Name)
+		}
+	}
+
 	if verbosity >= 2 {
 		fmt.Println("Found", len(addons), "AddOn directories")
 	}
@@ -33,6 +42,30 @@ func FindAddOns(AppFs afero.Fs) ([]AddOnDefinition, error) {
 	return addons, err
 }
 
+// Keeps only the highest precedence manifest for each directory, recording any that were ignored.
+func resolveManifestPrecedence(definitions []AddOnDefinition) []AddOnDefinition {
+	var resolved []AddOnDefinition
+	var index = map[string]int{}
+
+	for _, definition := range definitions {
+		i, exists := index[definition.Dir]
+		if !exists {
+			index[definition.Dir] = len(resolved)
+			resolved = append(resolved, definition)
+			continue
+		}
+
+		if definition.Precedence() < resolved[i].Precedence() {
+			definition.Shadowed = resolved[i].Name
+			resolved[i] = definition
+		} else {
+			resolved[i].Shadowed = definition.Name
+		}
+	}
+
+	return resolved
+}
+
 func getAddOnList(path string, addons *[]AddOnDefinition, err error) error {
 	var verbosity = viper.GetInt("verbosity")
 
@@ -49,7 +82,7 @@ func getAddOnList(path string, addons *[]AddOnDefinition, err error) error {
 		Dir:  strings.TrimPrefix(filepath.Dir(path), AddOnsPath()),
 	}
 
-	if filepath.Ext(md.Name) == ".txt" && ToKey(filepath.Base(md.Dir)) == md.Key() {
+	if md.Precedence() >= 0 && ToKey(filepath.Base(md.Dir)) == md.Key() {
 		if verbosity >= 3 {
 			fmt.Println("Found", md.Name)
 		}
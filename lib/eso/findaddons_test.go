@@ -60,3 +60,23 @@ func TestFindAddOnsError(t *testing.T) {
 	// Check that the function returned the expected message
 	assert.Len(t, addonList, 0, "expected 0 addons")
 }
+
+func TestFindAddOns_AddonExtension(t *testing.T) {
+	// Create a new in-memory file system
+	var fs = afero.NewMemMapFs()
+	viper.Set("eso_home", "/tmp/eso/Elder Scrolls Online")
+
+	// A modern addon with only an .addon manifest, and one shipping both manifests
+	_ = afero.WriteFile(fs, "/tmp/eso/Elder Scrolls Online/live/AddOns/Modern/Modern.addon", []byte("## Title: Modern"), 0644)
+	_ = afero.WriteFile(fs, "/tmp/eso/Elder Scrolls Online/live/AddOns/Both/Both.txt", []byte("## Title: Both (txt)"), 0644)
+	_ = afero.WriteFile(fs, "/tmp/eso/Elder Scrolls Online/live/AddOns/Both/Both.addon", []byte("## Title: Both (addon)"), 0644)
+
+	// Call the function we're testing
+	addonList, err := eso.FindAddOns(fs)
+
+	// Check that each directory is only reported once, preferring the .addon manifest
+	assert.Nil(t, err, "expected no error")
+	assert.Len(t, addonList, 2, "expected 2 addons")
+	assert.Contains(t, addonList, eso.AddOnDefinition{Name: "Modern.addon", Dir: filepath.Clean("/Modern")}, "expected Modern")
+	assert.Contains(t, addonList, eso.AddOnDefinition{Name: "Both.addon", Dir: filepath.Clean("/Both"), Shadowed: "Both.txt"}, "expected Both")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/fs"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/viper"
@@ -17,6 +18,11 @@ func (sv SavedVars) FullPath() string {
 	return filepath.Join(SavedVariablesPath(), sv.Name())
 }
 
+// Parse reads and parses the SavedVariables file.
+func (sv SavedVars) Parse(AppFs afero.Fs) (*LuaDocument, error) {
+	return ReadLua(AppFs, sv.FullPath())
+}
+
 func FindSavedVars(AppFs afero.Fs) ([]SavedVars, error) {
 	var err error
 	var savedVars []SavedVars
@@ -47,3 +53,93 @@ func FindSavedVars(AppFs afero.Fs) ([]SavedVars, error) {
 
 	return savedVars, err
 }
+
+// FindSavedVarsFile resolves name to a SavedVariables file. The name may be the path to a file, or the name of a
+// file within the SavedVariables directory, with or without its ".lua" extension (e.g. "MyAddon" or "myaddon.lua").
+func FindSavedVarsFile(AppFs afero.Fs, name string) (string, error) {
+	if ok, err := afero.Exists(AppFs, name); ok && err == nil {
+		if isDir, _ := afero.IsDir(AppFs, name); !isDir {
+			return name, nil
+		}
+	}
+
+	base := filepath.Base(name)
+	if !strings.EqualFold(filepath.Ext(base), ".lua") {
+		base += ".lua"
+	}
+
+	path := filepath.Join(SavedVariablesPath(), base)
+	if ok, err := afero.Exists(AppFs, path); ok && err == nil {
+		return path, nil
+	}
+
+	savedVars, err := FindSavedVars(AppFs)
+	if err != nil {
+		return "", err
+	}
+
+	for _, sv := range savedVars {
+		if !sv.IsDir() && strings.EqualFold(sv.Name(), base) {
+			return sv.FullPath(), nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find a SavedVariables file named %q in %q", name, SavedVariablesPath())
+}
+
+// SavedVarsFile is a SavedVariables file which was read and parsed (see ReadSavedVarsFiles).
+type SavedVarsFile struct {
+	Path     string
+	Size     int64
+	Document *LuaDocument
+}
+
+func (F SavedVarsFile) Name() string {
+	return filepath.Base(F.Path)
+}
+
+// ReadSavedVarsFiles reads and parses the named SavedVariables files (see FindSavedVarsFile), or every ".lua" file
+// within the SavedVariables directory if none are named. Files which can't be read or parsed are skipped, and their
+// errors returned alongside the files which could be; err is only returned if the files can't be found.
+func ReadSavedVarsFiles(AppFs afero.Fs, names ...string) (files []SavedVarsFile, skipped []error, err error) {
+	var paths []string
+
+	if len(names) > 0 {
+		for _, name := range names {
+			path, err := FindSavedVarsFile(AppFs, name)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			paths = append(paths, path)
+		}
+	} else {
+		savedVarFiles, err := FindSavedVars(AppFs)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, savedVars := range savedVarFiles {
+			if !savedVars.IsDir() && strings.EqualFold(filepath.Ext(savedVars.Name()), ".lua") {
+				paths = append(paths, savedVars.FullPath())
+			}
+		}
+	}
+
+	for _, path := range paths {
+		info, err := AppFs.Stat(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		document, err := ReadLua(AppFs, path)
+		if err != nil {
+			skipped = append(skipped, err)
+			continue
+		}
+
+		files = append(files, SavedVarsFile{Path: path, Size: info.Size(), Document: document})
+	}
+
+	return files, skipped, nil
+}
@@ -48,3 +48,54 @@ func TestFindSavedVarsError(t *testing.T) {
 	// Check that the function returned the expected message
 	assert.Len(t, savedVarsList, 0, "expected 0 SavedVariable files")
 }
+
+func TestSavedVars_Parse(t *testing.T) {
+	var fs = afero.NewMemMapFs()
+	viper.Set("eso_home", "/tmp/eso/Elder Scrolls Online")
+
+	_ = afero.WriteFile(fs, "/tmp/eso/Elder Scrolls Online/live/SavedVariables/MyAddon.lua", []byte("MyAddon_SV =\n{\n    [\"version\"] = 1,\n}\n"), 0644)
+
+	savedVarsList, err := eso.FindSavedVars(fs)
+	assert.Nil(t, err, "expected no error")
+	assert.Len(t, savedVarsList, 1)
+
+	document, err := savedVarsList[0].Parse(fs)
+	assert.Nil(t, err, "expected no error")
+	assert.Equal(t, float64(1), document.Get("MyAddon_SV").Get("version").Number)
+}
+
+func TestReadSavedVarsFiles(t *testing.T) {
+	var fs = afero.NewMemMapFs()
+	viper.Set("eso_home", "/tmp/eso/Elder Scrolls Online")
+
+	var dir = "/tmp/eso/Elder Scrolls Online/live/SavedVariables/"
+	_ = afero.WriteFile(fs, dir+"MyAddon.lua", []byte("MyAddon_SV =\n{\n}\n"), 0644)
+	_ = afero.WriteFile(fs, dir+"Other.LUA", []byte("Other_SV = 1\n"), 0644)
+	_ = afero.WriteFile(fs, dir+"Corrupt.lua", []byte("Corrupt_SV =\n{\n"), 0644)
+	_ = afero.WriteFile(fs, dir+"notes.txt", []byte("notes"), 0644)
+	_ = fs.MkdirAll(dir+"Folder.lua", 0755)
+
+	files, skipped, err := eso.ReadSavedVarsFiles(fs)
+	assert.NoError(t, err)
+	assert.Len(t, skipped, 1)
+	assert.ErrorContains(t, skipped[0], "Corrupt.lua")
+
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+		assert.NotNil(t, file.Document, file.Name())
+	}
+	assert.Equal(t, []string{"MyAddon.lua", "Other.LUA"}, names)
+	assert.Equal(t, int64(len("MyAddon_SV =\n{\n}\n")), files[0].Size)
+
+	files, skipped, err = eso.ReadSavedVarsFiles(fs, "other")
+	assert.NoError(t, err)
+	assert.Empty(t, skipped)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, dir+"Other.LUA", files[0].Path)
+		assert.Equal(t, []string{"Other_SV"}, files[0].Document.Names())
+	}
+
+	_, _, err = eso.ReadSavedVarsFiles(fs, "MyAddon", "Missing")
+	assert.Error(t, err)
+}
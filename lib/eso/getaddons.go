@@ -1,12 +1,9 @@
 package eso
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"unicode"
 
@@ -17,6 +14,7 @@ import (
 func GetAddOns(AppFs afero.Fs) (AddOns, []error) {
 	var errs = []error{}
 	var addons = AddOns{}
+	var instances = []AddOn{}
 	var verbosity = viper.GetInt("verbosity")
 
 	addonlist, err := FindAddOns(AppFs)
@@ -25,108 +23,38 @@ func GetAddOns(AppFs afero.Fs) (AddOns, []error) {
 		os.Exit(1)
 	}
 
-	var re = regexp.MustCompile(`##\s+(?P<Type>\w+):\s(?P<Data>.*)\s*$`)
-
 	for _, addonFile := range addonlist {
-		file, err := AppFs.Open(filepath.Join(AddOnsPath(), addonFile.Path()))
-		if err != nil {
-			errs = append(errs, fmt.Errorf("error opening file: %w", err))
-			continue
+		if verbosity >= 3 {
+			fmt.Printf("Parsing %s\n", addonFile.Path())
 		}
-		defer file.Close()
 
-		data, err := afero.ReadAll(file)
+		manifest, err := ReadManifest(AppFs, addonFile.Path())
 		if err != nil {
-			errs = append(errs, fmt.Errorf("error reading file: %w", err))
+			errs = append(errs, err)
 			continue
 		}
 
-		addon, err := NewAddOn(addonFile.Key())
+		addon, err := NewAddOnFromManifest(addonFile.Key(), manifest)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("could not create addon: %w", err))
 			continue
 		}
 
-		if verbosity >= 3 {
-			fmt.Printf("Parsing %s\n", addonFile.Path())
-		}
-
 		addon.SetDir(addonFile.Dir)
+		addon.SetShadowed(addonFile.Shadowed)
 
-		// Create a reader from the byte slice
-		reader := bufio.NewReader(bytes.NewReader(data))
-
-		// Read lines until EOF
-		for {
-			line, err := reader.ReadBytes('\n')
-			if err != nil {
-				break // EOF or error
-			}
-
-			// Remove the trailing newline character
-			line = bytes.TrimSuffix(line, []byte("\n"))
-
-			matches := re.FindStringSubmatch(string(line))
-			if len(matches) > 1 {
-				typeIndex := re.SubexpIndex("Type")
-				dataIndex := re.SubexpIndex("Data")
-
-				rawString := matches[dataIndex]
-				cleanedString := cleanString(rawString)
-
-				switch matches[typeIndex] {
-				case "Title":
-					addon.Title = cleanedString
-				case "Description":
-					addon.Description = rawString
-				case "Author":
-					addon.Author = rawString
-				case "Contributors":
-					addon.Contributors = rawString
-				case "Version":
-					addon.Version = strings.TrimPrefix(cleanedString, "v")
-				case "AddOnVersion", "AddonVersion":
-					addon.AddOnVersion = cleanedString
-				case "APIVersion":
-					addon.APIVersion = cleanedString
-				case "SavedVariables":
-					addon.SavedVariables = strings.Split(cleanedString, " ")
-				case "DependsOn":
-					addon.DependsOn = strings.Split(cleanedString, " ")
-				case "OptionalDependsOn":
-					addon.OptionalDependsOn = strings.Split(cleanedString, " ")
-				case "IsLibrary":
-					addon.SetLibrary(cleanedString == "true")
-				default:
-					if verbosity >= 3 {
-						fmt.Println(fmt.Errorf("unknown type: %s with value: %s", matches[typeIndex], matches[dataIndex]))
-					}
-				}
-			}
-		}
-
-		// Don't add submodules to the list (for now)
-		if dup, exists := addons.Find(addon.Key()); exists {
-			if !addon.IsSubmodule() {
-				if dup.IsSubmodule() {
-					addons.Update(addon)
-				} else {
-					fmt.Println(fmt.Errorf("duplicate addons found for %s\n%v\n%v", addon.Key(), addon, dup))
-				}
-			}
-
-			continue
-		}
-
-		if addon.Validate() {
-			addons.Add(addon)
-		} else {
+		if !addon.Validate() {
 			for _, err := range addon.Errors() {
 				errs = append(errs, fmt.Errorf("addon %s: %w", addon.Key(), err))
 			}
+			continue
 		}
+
+		instances = append(instances, addon)
 	}
 
+	addons = arbitrateAddOns(instances)
+
 	markDependencies(&addons)
 
 	return addons, errs
@@ -147,12 +75,8 @@ func DependencyName(input string) []string {
 
 func markDependencies(addons *AddOns) {
 	for key, addon := range *addons {
-		if len(addon.DependsOn) == 0 {
-			continue
-		}
-
 		// Mark submodules as dependencies (of their parent)
-		if addon.IsSubmodule() {
+		if addon.Parent() != "" {
 			addon.SetDependency(true)
 			addons.Update(addon)
 		}
@@ -160,7 +84,7 @@ func markDependencies(addons *AddOns) {
 		for _, dependency := range addon.DependsOn {
 			dependencyName := DependencyName(dependency)[0]
 
-			// Skip self-references
+			// Skip self-references (see DependencyGraph.SelfReferences)
 			if dependencyName == "" || ToKey(dependencyName) == key {
 				continue
 			}
@@ -169,8 +93,74 @@ func markDependencies(addons *AddOns) {
 				depaddon.SetDependency(true)
 				addons.Update(depaddon)
 			} else {
-				fmt.Println(fmt.Errorf("missing Dependency: %s", dependencyName))
+				fmt.Fprintln(os.Stderr, fmt.Errorf("missing Dependency: %s", dependencyName))
 			}
 		}
 	}
 }
+
+// Links each AddOn to the AddOn whose directory it's embedded in, then decides which of several installed
+// copies of the same AddOn the game will load. The remaining copies are kept with the winner (see AddOn.Copies).
+func arbitrateAddOns(instances []AddOn) AddOns {
+	var addons = AddOns{}
+
+	for i := range instances {
+		if parent, found := findParent(instances, instances[i]); found {
+			instances[i].meta.parent = parent.Key()
+		}
+	}
+
+	for _, addon := range instances {
+		current, exists := addons[addon.Key()]
+		if !exists {
+			addons.Add(addon)
+			continue
+		}
+
+		if loadsBefore(addon, current) {
+			addon.meta.copies = append(current.meta.copies, current.withoutCopies())
+			addons.Update(addon)
+		} else {
+			current.meta.copies = append(current.meta.copies, addon)
+			addons.Update(current)
+		}
+	}
+
+	return addons
+}
+
+// Returns the AddOn whose directory most closely encloses the given AddOn's directory.
+func findParent(instances []AddOn, addon AddOn) (AddOn, bool) {
+	var parent AddOn
+	var found bool
+
+	for _, candidate := range instances {
+		if !isWithinDir(addon.Dir(), candidate.Dir()) {
+			continue
+		}
+
+		if !found || len(candidate.Dir()) > len(parent.Dir()) {
+			parent, found = candidate, true
+		}
+	}
+
+	return parent, found
+}
+
+// Returns true if dir is nested somewhere below parent.
+func isWithinDir(dir string, parent string) bool {
+	return parent != "" && strings.HasPrefix(dir, strings.TrimSuffix(parent, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// Returns true if the game would load a in preference to b, when both are copies of the same AddOn.
+// The game loads the copy with the highest AddOnVersion; we break ties in favor of the least nested copy.
+func loadsBefore(a AddOn, b AddOn) bool {
+	versionA, _ := a.AddOnVersionNumber()
+	versionB, _ := b.AddOnVersionNumber()
+
+	if versionA != versionB {
+		return versionA > versionB
+	}
+
+	return a.Depth() < b.Depth()
+}
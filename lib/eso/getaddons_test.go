@@ -20,6 +20,23 @@ func init() {
 	viper.Set("eso_home", "/tmp/eso")
 }
 
+// addOnsFs returns an in-memory filesystem holding the given files, by path within the game's "live" directory, such as
+// "AddOns/MyAddon/MyAddon.txt" or "SavedVariables/MyAddon.lua"
+func addOnsFs(t *testing.T, files map[string]string) afero.Fs {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	viper.Set("eso_home", "/tmp/eso")
+
+	require.NoError(t, fs.MkdirAll(eso.AddOnsPath(), 0755))
+
+	for path, data := range files {
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(eso.ESOHome(), "live", filepath.FromSlash(path)), []byte(data), 0644))
+	}
+
+	return fs
+}
+
 func TestGetAddOns_EmptyAddonList(t *testing.T) {
 	// Arrange
 	expected := eso.AddOns{}
@@ -64,3 +81,50 @@ func TestGetAddOns_MissingRequiredTitle(t *testing.T) {
 
 	assert.Equal(t, expectedTitle, addon.Title)
 }
+
+func TestGetAddOns_RepeatedDirectivesAccumulate(t *testing.T) {
+	// Arrange
+	fs := afero.NewMemMapFs()
+	addonName := "RepeatedDirectives"
+	data := []byte("## Title: Repeated Directives\r\n## DependsOn: LibOne\r\n## DependsOn: LibTwo>=2\r\nRepeatedDirectives.lua\r\n## IsLibrary: true")
+
+	err := afero.WriteFile(fs, filepath.Join(eso.AddOnsPath(), addonName, addonName+".txt"), data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	addons, actualErrs := eso.GetAddOns(fs)
+	require.Empty(t, actualErrs)
+
+	// Assert
+	addon, found := addons.Find(addonName)
+	require.True(t, found)
+
+	assert.Equal(t, []string{"LibOne", "LibTwo>=2"}, addon.DependsOn)
+	assert.True(t, addon.IsLibrary())
+	assert.Equal(t, []string{"RepeatedDirectives.lua"}, addon.Manifest().Files())
+	assert.Equal(t, data, addon.Manifest().Bytes())
+}
+
+func TestGetAddOns_PrefersAddonManifest(t *testing.T) {
+	// Arrange
+	fs := afero.NewMemMapFs()
+	addonName := "BothManifests"
+	dir := filepath.Join(eso.AddOnsPath(), addonName)
+
+	_ = afero.WriteFile(fs, filepath.Join(dir, addonName+".txt"), []byte("## Title: Legacy\n"), 0644)
+	_ = afero.WriteFile(fs, filepath.Join(dir, addonName+".addon"), []byte("## Title: Modern\n"), 0644)
+
+	// Act
+	addons, actualErrs := eso.GetAddOns(fs)
+	require.Empty(t, actualErrs)
+	require.Len(t, addons, 1)
+
+	// Assert
+	addon, found := addons.Find(addonName)
+	require.True(t, found)
+
+	assert.Equal(t, "Modern", addon.Title)
+	assert.Equal(t, addonName+".txt", addon.Shadowed())
+}
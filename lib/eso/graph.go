@@ -0,0 +1,397 @@
+package eso
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EdgeType identifies whether a dependency is required or optional.
+type EdgeType int
+
+const (
+	RequiredEdge EdgeType = iota // From a DependsOn directive
+	OptionalEdge                 // From an OptionalDependsOn directive
+)
+
+// String returns "required" or "optional".
+func (T EdgeType) String() string {
+	if T == OptionalEdge {
+		return "optional"
+	}
+
+	return "required"
+}
+
+// MarshalText allows the EdgeType to be marshalled as its String() value.
+func (T EdgeType) MarshalText() ([]byte, error) {
+	return []byte(T.String()), nil
+}
+
+// GraphNode represents a single AddOn in a DependencyGraph.
+// Dependencies which aren't installed are included as Missing nodes.
+type GraphNode struct {
+	Key          string `json:"key"`
+	Title        string `json:"title"`
+	AddOnVersion string `json:"addOnVersion"`
+	Library      bool   `json:"library"`
+	Missing      bool   `json:"missing"`
+	Dependents   int    `json:"dependents"` // Number of AddOns depending on this one
+}
+
+// Label returns a display name for the node.
+func (N GraphNode) Label() string {
+	if N.Title == "" {
+		return N.Key
+	}
+
+	return N.Title
+}
+
+// GraphEdge represents a dependency of one AddOn (From) on another (To).
+type GraphEdge struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Type       EdgeType `json:"type"`
+	Constraint string   `json:"constraint,omitempty"` // Version constraint, e.g. ">=71"
+	Outdated   bool     `json:"outdated,omitempty"`   // True if the installed dependency doesn't meet the constraint
+}
+
+// DependencyGraph is a directed graph of AddOns and the AddOns they depend upon.
+type DependencyGraph struct {
+	Nodes map[string]*GraphNode
+	Edges []GraphEdge
+}
+
+// NewDependencyGraph builds a DependencyGraph from the DependsOn and OptionalDependsOn directives of the AddOns.
+func NewDependencyGraph(addons AddOns) *DependencyGraph {
+	graph := &DependencyGraph{Nodes: map[string]*GraphNode{}}
+
+	for _, key := range addons.Keys() {
+		addon := addons[key]
+
+		graph.Nodes[key] = &GraphNode{
+			Key:          key,
+			Title:        addon.CleanTitle(),
+			AddOnVersion: addon.AddOnVersion,
+			Library:      addon.IsLibrary(),
+		}
+	}
+
+	for _, key := range addons.Keys() {
+		addon := addons[key]
+
+		graph.addEdges(addons, key, addon.Dependencies(), RequiredEdge)
+		graph.addEdges(addons, key, addon.OptionalDependencies(), OptionalEdge)
+	}
+
+	return graph
+}
+
+func (G *DependencyGraph) addEdges(addons AddOns, from string, dependencies []Dependency, edgeType EdgeType) {
+	for _, dependency := range dependencies {
+		edge := GraphEdge{From: from, To: ToKey(dependency.Name), Type: edgeType, Constraint: dependency.Constraint()}
+
+		if installed, exists := addons.Find(dependency.Name); exists {
+			edge.To = installed.Key()
+			edge.Outdated = !dependency.SatisfiedBy(installed)
+		} else if _, exists := G.Nodes[edge.To]; !exists {
+			G.Nodes[edge.To] = &GraphNode{Key: edge.To, Missing: true}
+		}
+
+		G.Nodes[edge.To].Dependents++
+		G.Edges = append(G.Edges, edge)
+	}
+}
+
+// Keys returns the keys of every node in the graph, sorted alphabetically.
+func (G DependencyGraph) Keys() []string {
+	keys := make([]string, 0, len(G.Nodes))
+
+	for key := range G.Nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Find returns the node for the given AddOn name, and whether it exists in the graph.
+func (G DependencyGraph) Find(name string) (*GraphNode, bool) {
+	node, exists := G.Nodes[ToKey(name)]
+	return node, exists
+}
+
+// DependenciesOf returns the edges leading from the given AddOn to the AddOns it depends upon.
+func (G DependencyGraph) DependenciesOf(key string) []GraphEdge {
+	edges := []GraphEdge{}
+
+	for _, edge := range G.Edges {
+		if edge.From == key {
+			edges = append(edges, edge)
+		}
+	}
+
+	return edges
+}
+
+// DependentsOf returns the edges leading to the given AddOn from the AddOns which depend upon it.
+func (G DependencyGraph) DependentsOf(key string) []GraphEdge {
+	edges := []GraphEdge{}
+
+	for _, edge := range G.Edges {
+		if edge.To == key {
+			edges = append(edges, edge)
+		}
+	}
+
+	return edges
+}
+
+// Subgraph returns the part of the graph reachable from the given AddOns by following their dependencies.
+// Optional dependencies are only followed if includeOptional is true.
+func (G DependencyGraph) Subgraph(includeOptional bool, roots ...string) (*DependencyGraph, error) {
+	var queue []string
+	var visited = map[string]bool{}
+
+	for _, root := range roots {
+		node, exists := G.Find(root)
+		if !exists {
+			return nil, fmt.Errorf("AddOn %q is not installed", root)
+		}
+
+		queue = append(queue, node.Key)
+	}
+
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+
+		for _, edge := range G.DependenciesOf(key) {
+			if edge.Type == RequiredEdge || includeOptional {
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	return G.filter(func(edge GraphEdge) bool {
+		return visited[edge.From] && visited[edge.To] && (edge.Type == RequiredEdge || includeOptional)
+	}, visited), nil
+}
+
+// WithoutOptional returns a copy of the graph containing only required dependencies.
+// Missing nodes which are only optional dependencies are dropped.
+func (G DependencyGraph) WithoutOptional() *DependencyGraph {
+	keep := map[string]bool{}
+
+	for key, node := range G.Nodes {
+		keep[key] = !node.Missing
+	}
+
+	for _, edge := range G.Edges {
+		if edge.Type == RequiredEdge {
+			keep[edge.To] = true
+		}
+	}
+
+	return G.filter(func(edge GraphEdge) bool { return edge.Type == RequiredEdge }, keep)
+}
+
+// Returns a copy of the graph with only the nodes in keep, and the edges accepted by the filter.
+func (G DependencyGraph) filter(accept func(GraphEdge) bool, keep map[string]bool) *DependencyGraph {
+	graph := &DependencyGraph{Nodes: map[string]*GraphNode{}}
+
+	for key, node := range G.Nodes {
+		if keep[key] {
+			clone := *node
+			clone.Dependents = 0
+			graph.Nodes[key] = &clone
+		}
+	}
+
+	for _, edge := range G.Edges {
+		if accept(edge) {
+			graph.Nodes[edge.To].Dependents++
+			graph.Edges = append(graph.Edges, edge)
+		}
+	}
+
+	return graph
+}
+
+// ToDOT returns the graph in Graphviz DOT format.
+// Optional dependencies are drawn dashed, libraries as boxes, and missing AddOns in red.
+func (G DependencyGraph) ToDOT() string {
+	var output strings.Builder
+
+	output.WriteString("digraph AddOns {\n")
+	output.WriteString("  rankdir=LR;\n")
+	output.WriteString("  node [shape=ellipse];\n")
+
+	for _, key := range G.Keys() {
+		node := G.Nodes[key]
+		attributes := []string{fmt.Sprintf("label=%q", node.Label())}
+
+		if node.Library {
+			attributes = append(attributes, "shape=box")
+		}
+
+		if node.Missing {
+			attributes = append(attributes, "color=red", "fontcolor=red", "style=dashed")
+		}
+
+		fmt.Fprintf(&output, "  %q [%s];\n", key, strings.Join(attributes, ", "))
+	}
+
+	for _, edge := range G.Edges {
+		attributes := []string{}
+
+		if edge.Type == OptionalEdge {
+			attributes = append(attributes, "style=dashed")
+		}
+
+		if edge.Constraint != "" {
+			attributes = append(attributes, fmt.Sprintf("label=%q", edge.Constraint))
+		}
+
+		if edge.Outdated || G.Nodes[edge.To].Missing {
+			attributes = append(attributes, "color=red")
+		}
+
+		if len(attributes) == 0 {
+			fmt.Fprintf(&output, "  %q -> %q;\n", edge.From, edge.To)
+		} else {
+			fmt.Fprintf(&output, "  %q -> %q [%s];\n", edge.From, edge.To, strings.Join(attributes, ", "))
+		}
+	}
+
+	output.WriteString("}\n")
+
+	return output.String()
+}
+
+// ToMermaid returns the graph as a Mermaid flowchart.
+// Optional dependencies are drawn dotted, and missing AddOns are styled with the "missing" class.
+func (G DependencyGraph) ToMermaid() string {
+	var output strings.Builder
+	var ids = map[string]string{}
+	var missing []string
+
+	output.WriteString("graph LR\n")
+
+	for i, key := range G.Keys() {
+		node := G.Nodes[key]
+		ids[key] = fmt.Sprintf("n%d", i)
+
+		label := strings.ReplaceAll(node.Label(), `"`, "#quot;")
+		if node.Library {
+			fmt.Fprintf(&output, "  %s[\"%s\"]\n", ids[key], label)
+		} else {
+			fmt.Fprintf(&output, "  %s(\"%s\")\n", ids[key], label)
+		}
+
+		if node.Missing {
+			missing = append(missing, ids[key])
+		}
+	}
+
+	for _, edge := range G.Edges {
+		arrow := "-->"
+		if edge.Type == OptionalEdge {
+			arrow = "-.->"
+		}
+
+		if edge.Constraint != "" {
+			fmt.Fprintf(&output, "  %s %s|\"%s\"| %s\n", ids[edge.From], arrow, edge.Constraint, ids[edge.To])
+		} else {
+			fmt.Fprintf(&output, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+		}
+	}
+
+	if len(missing) > 0 {
+		output.WriteString("  classDef missing fill:#fdd,stroke:#f00,stroke-dasharray:5 5\n")
+		fmt.Fprintf(&output, "  class %s missing\n", strings.Join(missing, ","))
+	}
+
+	return output.String()
+}
+
+// ToGraphML returns the graph in GraphML format.
+func (G DependencyGraph) ToGraphML() string {
+	var output strings.Builder
+
+	output.WriteString(xml.Header)
+	output.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	output.WriteString(`  <key id="title" for="node" attr.name="title" attr.type="string"/>` + "\n")
+	output.WriteString(`  <key id="addOnVersion" for="node" attr.name="addOnVersion" attr.type="string"/>` + "\n")
+	output.WriteString(`  <key id="library" for="node" attr.name="library" attr.type="boolean"/>` + "\n")
+	output.WriteString(`  <key id="missing" for="node" attr.name="missing" attr.type="boolean"/>` + "\n")
+	output.WriteString(`  <key id="dependents" for="node" attr.name="dependents" attr.type="int"/>` + "\n")
+	output.WriteString(`  <key id="type" for="edge" attr.name="type" attr.type="string"/>` + "\n")
+	output.WriteString(`  <key id="constraint" for="edge" attr.name="constraint" attr.type="string"/>` + "\n")
+	output.WriteString(`  <key id="outdated" for="edge" attr.name="outdated" attr.type="boolean"/>` + "\n")
+	output.WriteString(`  <graph id="AddOns" edgedefault="directed">` + "\n")
+
+	for _, key := range G.Keys() {
+		node := G.Nodes[key]
+
+		fmt.Fprintf(&output, "    <node id=\"%s\">\n", xmlEscape(key))
+		fmt.Fprintf(&output, "      <data key=\"title\">%s</data>\n", xmlEscape(node.Label()))
+		fmt.Fprintf(&output, "      <data key=\"addOnVersion\">%s</data>\n", xmlEscape(node.AddOnVersion))
+		fmt.Fprintf(&output, "      <data key=\"library\">%v</data>\n", node.Library)
+		fmt.Fprintf(&output, "      <data key=\"missing\">%v</data>\n", node.Missing)
+		fmt.Fprintf(&output, "      <data key=\"dependents\">%d</data>\n", node.Dependents)
+		output.WriteString("    </node>\n")
+	}
+
+	for i, edge := range G.Edges {
+		fmt.Fprintf(&output, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, xmlEscape(edge.From), xmlEscape(edge.To))
+		fmt.Fprintf(&output, "      <data key=\"type\">%s</data>\n", edge.Type)
+		if edge.Constraint != "" {
+			fmt.Fprintf(&output, "      <data key=\"constraint\">%s</data>\n", xmlEscape(edge.Constraint))
+		}
+		fmt.Fprintf(&output, "      <data key=\"outdated\">%v</data>\n", edge.Outdated)
+		output.WriteString("    </edge>\n")
+	}
+
+	output.WriteString("  </graph>\n")
+	output.WriteString("</graphml>\n")
+
+	return output.String()
+}
+
+// ToJson returns the graph in JSON format, as a list of nodes and a list of edges.
+func (G DependencyGraph) ToJson() ([]byte, error) {
+	var graph = struct {
+		Nodes []*GraphNode `json:"nodes"`
+		Edges []GraphEdge  `json:"edges"`
+	}{Nodes: []*GraphNode{}, Edges: []GraphEdge{}}
+
+	for _, key := range G.Keys() {
+		graph.Nodes = append(graph.Nodes, G.Nodes[key])
+	}
+
+	graph.Edges = append(graph.Edges, G.Edges...)
+
+	output, err := json.Marshal(graph)
+	if err != nil {
+		return []byte{}, fmt.Errorf("error marshalling JSON: %w", err)
+	}
+
+	return output, nil
+}
+
+func xmlEscape(input string) string {
+	var output strings.Builder
+
+	_ = xml.EscapeText(&output, []byte(input))
+
+	return output.String()
+}
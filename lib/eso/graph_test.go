@@ -0,0 +1,170 @@
+package eso_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func graphAddOns(t *testing.T) eso.AddOns {
+	t.Helper()
+
+	addons, errs := eso.GetAddOns(addOnsFs(t, map[string]string{
+		"AddOns/MyMap/MyMap.txt":       "## Title: My Map\n## DependsOn: LibGPS>=71 LibMissing\n## OptionalDependsOn: LibOptional LibExtra\n",
+		"AddOns/LibGPS/LibGPS.txt":     "## Title: LibGPS\n## AddOnVersion: 70\n## IsLibrary: true\n",
+		"AddOns/LibExtra/LibExtra.txt": "## Title: LibExtra\n## IsLibrary: true\n",
+		"AddOns/Other/Other.txt":       "## Title: Other\n## DependsOn: LibGPS\n",
+	}))
+	require.Empty(t, errs)
+
+	return addons
+}
+
+func TestNewDependencyGraph(t *testing.T) {
+	graph := eso.NewDependencyGraph(graphAddOns(t))
+
+	assert.Equal(t, []string{"LibExtra", "LibGPS", "LibMissing", "LibOptional", "MyMap", "Other"}, graph.Keys())
+	assert.True(t, graph.Nodes["LibMissing"].Missing)
+	assert.True(t, graph.Nodes["LibOptional"].Missing)
+	assert.False(t, graph.Nodes["LibGPS"].Missing)
+	assert.True(t, graph.Nodes["LibGPS"].Library)
+	assert.Equal(t, 2, graph.Nodes["LibGPS"].Dependents)
+	assert.Equal(t, 0, graph.Nodes["MyMap"].Dependents)
+
+	edges := graph.DependenciesOf("MyMap")
+	require.Len(t, edges, 4)
+	assert.Equal(t, eso.GraphEdge{From: "MyMap", To: "LibGPS", Type: eso.RequiredEdge, Constraint: ">=71", Outdated: true}, edges[0])
+	assert.Equal(t, eso.OptionalEdge, edges[2].Type)
+
+	assert.Len(t, graph.DependentsOf("LibGPS"), 2)
+}
+
+func TestDependencyGraph_Subgraph(t *testing.T) {
+	graph := eso.NewDependencyGraph(graphAddOns(t))
+
+	subgraph, err := graph.Subgraph(false, "MyMap")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LibGPS", "LibMissing", "MyMap"}, subgraph.Keys())
+	assert.Len(t, subgraph.Edges, 2)
+	assert.Equal(t, 1, subgraph.Nodes["LibGPS"].Dependents)
+
+	subgraph, err = graph.Subgraph(true, "MyMap")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"LibExtra", "LibGPS", "LibMissing", "LibOptional", "MyMap"}, subgraph.Keys())
+
+	_, err = graph.Subgraph(false, "Unknown")
+	assert.Error(t, err)
+}
+
+func TestDependencyGraph_WithoutOptional(t *testing.T) {
+	graph := eso.NewDependencyGraph(graphAddOns(t)).WithoutOptional()
+
+	assert.Equal(t, []string{"LibExtra", "LibGPS", "LibMissing", "MyMap", "Other"}, graph.Keys())
+	assert.Len(t, graph.Edges, 3)
+	assert.Equal(t, 0, graph.Nodes["LibExtra"].Dependents)
+}
+
+func TestDependencyGraph_ToDOT(t *testing.T) {
+	graph, err := eso.NewDependencyGraph(graphAddOns(t)).Subgraph(true, "MyMap")
+	require.NoError(t, err)
+
+	output := graph.ToDOT()
+
+	assert.Contains(t, output, "digraph AddOns {\n")
+	assert.Contains(t, output, `"LibGPS" [label="LibGPS", shape=box];`)
+	assert.Contains(t, output, `"LibMissing" [label="LibMissing", color=red, fontcolor=red, style=dashed];`)
+	assert.Contains(t, output, `"MyMap" -> "LibGPS" [label=">=71", color=red];`)
+	assert.Contains(t, output, `"MyMap" -> "LibExtra" [style=dashed];`)
+}
+
+func TestDependencyGraph_ToMermaid(t *testing.T) {
+	graph, err := eso.NewDependencyGraph(graphAddOns(t)).Subgraph(false, "MyMap")
+	require.NoError(t, err)
+
+	expected := "graph LR\n" +
+		"  n0[\"LibGPS\"]\n" +
+		"  n1(\"LibMissing\")\n" +
+		"  n2(\"My Map\")\n" +
+		"  n2 -->|\">=71\"| n0\n" +
+		"  n2 --> n1\n" +
+		"  classDef missing fill:#fdd,stroke:#f00,stroke-dasharray:5 5\n" +
+		"  class n1 missing\n"
+
+	assert.Equal(t, expected, graph.ToMermaid())
+}
+
+func TestDependencyGraph_ToGraphML(t *testing.T) {
+	graph, err := eso.NewDependencyGraph(graphAddOns(t)).Subgraph(false, "Other")
+	require.NoError(t, err)
+
+	output := graph.ToGraphML()
+
+	assert.Contains(t, output, `<graph id="AddOns" edgedefault="directed">`)
+	assert.Contains(t, output, `<node id="LibGPS">`)
+	assert.Contains(t, output, `<edge id="e0" source="Other" target="LibGPS">`)
+	assert.Contains(t, output, `<data key="type">required</data>`)
+}
+
+func TestDependencyGraph_ToJson(t *testing.T) {
+	graph, err := eso.NewDependencyGraph(graphAddOns(t)).Subgraph(false, "Other")
+	require.NoError(t, err)
+
+	output, err := graph.ToJson()
+	require.NoError(t, err)
+
+	expected := `{
+		"nodes": [
+			{"key":"LibGPS","title":"LibGPS","addOnVersion":"70","library":true,"missing":false,"dependents":1},
+			{"key":"Other","title":"Other","addOnVersion":"","library":false,"missing":false,"dependents":0}
+		],
+		"edges": [{"from":"Other","to":"LibGPS","type":"required"}]
+	}`
+	assert.JSONEq(t, expected, string(output))
+	assert.True(t, json.Valid(output))
+}
+
+func TestDependencyGraph_MissingDependencyOutput(t *testing.T) {
+	stdout, stderr := captureOutput(t, func() {
+		graph := eso.NewDependencyGraph(graphAddOns(t))
+
+		output, err := graph.ToJson()
+		require.NoError(t, err)
+		fmt.Print(string(output))
+	})
+
+	// Only the graph itself should be written to stdout, so it can be piped to other tools
+	assert.True(t, json.Valid([]byte(stdout)))
+	assert.Contains(t, stderr, "missing Dependency: LibMissing")
+}
+
+// Returns what was written to stdout and stderr while running the given function
+func captureOutput(t *testing.T, run func()) (string, string) {
+	t.Helper()
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	require.NoError(t, err)
+	stderrReader, stderrWriter, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdoutWriter, stderrWriter
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	run()
+
+	require.NoError(t, stdoutWriter.Close())
+	require.NoError(t, stderrWriter.Close())
+
+	stdoutOutput, err := io.ReadAll(stdoutReader)
+	require.NoError(t, err)
+	stderrOutput, err := io.ReadAll(stderrReader)
+	require.NoError(t, err)
+
+	return string(stdoutOutput), string(stderrOutput)
+}
@@ -0,0 +1,206 @@
+package eso
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LoadFailureReason describes why the game won't load an AddOn.
+type LoadFailureReason int
+
+const (
+	LoadDisabled           LoadFailureReason = iota // The AddOn has been disabled
+	LoadMissingDependency                           // A required dependency isn't installed
+	LoadOutdatedDependency                          // A required dependency is older than the version required
+	LoadFailedDependency                            // A required dependency won't load itself
+	LoadCircularDependency                          // The AddOn depends upon itself through other AddOns
+)
+
+// String returns a short description of the reason.
+func (R LoadFailureReason) String() string {
+	switch R {
+	case LoadDisabled:
+		return "disabled"
+	case LoadMissingDependency:
+		return "missing dependency"
+	case LoadOutdatedDependency:
+		return "outdated dependency"
+	case LoadFailedDependency:
+		return "failed dependency"
+	case LoadCircularDependency:
+		return "circular dependency"
+	default:
+		return "unknown"
+	}
+}
+
+// LoadFailure describes an AddOn the game won't load, and why.
+type LoadFailure struct {
+	Key        string
+	Reason     LoadFailureReason
+	Dependency string   // The dependency responsible for the failure, if any
+	Constraint string   // Version constraint of an outdated dependency, e.g. ">=71"
+	Cause      string   // Key of the AddOn whose failure started the chain, for failed dependencies
+	Loop       []string // Every AddOn in the loop, for circular dependencies
+}
+
+// String returns a description of the failure, e.g. "requires LibGPS >=71, but an older version is installed".
+func (F LoadFailure) String() string {
+	switch F.Reason {
+	case LoadDisabled:
+		return "disabled"
+	case LoadMissingDependency:
+		return fmt.Sprintf("requires %s, which isn't installed", F.Dependency)
+	case LoadOutdatedDependency:
+		return fmt.Sprintf("requires %s %s, but an older version is installed", F.Dependency, F.Constraint)
+	case LoadFailedDependency:
+		return fmt.Sprintf("requires %s, which won't load", F.Dependency)
+	case LoadCircularDependency:
+		return fmt.Sprintf("circular dependency between %s", strings.Join(F.Loop, ", "))
+	default:
+		return F.Reason.String()
+	}
+}
+
+// LoadPlan is the result of simulating how the game resolves the installed AddOns.
+type LoadPlan struct {
+	Order    []string      // Keys of the AddOns which will load, in the order they'll be loaded
+	Failures []LoadFailure // AddOns which won't load, sorted by key
+}
+
+// Failure returns the failure for the given AddOn, and whether it failed to load.
+func (P LoadPlan) Failure(key string) (LoadFailure, bool) {
+	for _, failure := range P.Failures {
+		if failure.Key == key {
+			return failure, true
+		}
+	}
+
+	return LoadFailure{}, false
+}
+
+// LoadOrder simulates how the game resolves the installed AddOns in the graph, with the given AddOns disabled.
+//
+// AddOns fail to load when they're disabled, part of a dependency loop, or when a required dependency is missing,
+// too old, or fails to load itself. The remaining AddOns are ordered so that each loads after its required and
+// (installed) optional dependencies, otherwise alphabetically.
+func (G DependencyGraph) LoadOrder(disabled ...string) LoadPlan {
+	var plan LoadPlan
+	var failures = map[string]LoadFailure{}
+
+	for _, name := range disabled {
+		if node, exists := G.Find(name); exists && !node.Missing {
+			failures[node.Key] = LoadFailure{Key: node.Key, Reason: LoadDisabled}
+		}
+	}
+
+	for _, cycle := range G.Cycles() {
+		for _, key := range cycle.AddOns {
+			if _, failed := failures[key]; !failed {
+				failures[key] = LoadFailure{Key: key, Reason: LoadCircularDependency, Loop: cycle.AddOns}
+			}
+		}
+	}
+
+	// Keep failing AddOns whose required dependencies can't be loaded, until nothing changes
+	for changed := true; changed; {
+		changed = false
+
+		for _, key := range G.Keys() {
+			if _, failed := failures[key]; failed || G.Nodes[key].Missing {
+				continue
+			}
+
+			if failure, failed := G.checkDependencies(key, failures); failed {
+				failures[key], changed = failure, true
+			}
+		}
+	}
+
+	for _, key := range G.Keys() {
+		if failure, failed := failures[key]; failed {
+			plan.Failures = append(plan.Failures, failure)
+		}
+	}
+
+	plan.Order = G.sortLoadable(failures)
+
+	return plan
+}
+
+// Returns the first reason (if any) the AddOn can't load because of one of its required dependencies.
+func (G DependencyGraph) checkDependencies(key string, failures map[string]LoadFailure) (LoadFailure, bool) {
+	for _, edge := range G.DependenciesOf(key) {
+		if edge.Type != RequiredEdge || edge.To == key {
+			continue
+		}
+
+		switch dependency, failed := failures[edge.To]; {
+		case G.Nodes[edge.To].Missing:
+			return LoadFailure{Key: key, Reason: LoadMissingDependency, Dependency: edge.To}, true
+		case edge.Outdated:
+			return LoadFailure{Key: key, Reason: LoadOutdatedDependency, Dependency: edge.To, Constraint: edge.Constraint}, true
+		case failed:
+			cause := dependency.Cause
+			if cause == "" {
+				cause = dependency.Key
+			}
+
+			return LoadFailure{Key: key, Reason: LoadFailedDependency, Dependency: edge.To, Cause: cause}, true
+		}
+	}
+
+	return LoadFailure{}, false
+}
+
+// Returns the AddOns which will load, ordered so that every AddOn comes after its dependencies (Kahn's algorithm),
+// picking alphabetically whenever more than one AddOn is ready to load.
+func (G DependencyGraph) sortLoadable(failures map[string]LoadFailure) []string {
+	var order = []string{}
+	var ready []string
+	var waiting = map[string]int{}
+	var dependents = map[string][]string{}
+
+	loadable := func(key string) bool {
+		_, failed := failures[key]
+		return !failed && !G.Nodes[key].Missing
+	}
+
+	for _, key := range G.Keys() {
+		if loadable(key) {
+			waiting[key] = 0
+		}
+	}
+
+	for _, edge := range G.Edges {
+		if edge.From == edge.To || !loadable(edge.From) || !loadable(edge.To) {
+			continue
+		}
+
+		waiting[edge.From]++
+		dependents[edge.To] = append(dependents[edge.To], edge.From)
+	}
+
+	for key, count := range waiting {
+		if count == 0 {
+			ready = append(ready, key)
+		}
+	}
+
+	for len(ready) > 0 {
+		sort.Strings(ready)
+
+		key := ready[0]
+		ready = ready[1:]
+		order = append(order, key)
+
+		for _, dependent := range dependents[key] {
+			if waiting[dependent]--; waiting[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	return order
+}
@@ -0,0 +1,102 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadOrderGraph() *eso.DependencyGraph {
+	graph := &eso.DependencyGraph{Nodes: map[string]*eso.GraphNode{}}
+
+	for _, key := range []string{"Top", "MyMap", "LibMid", "LibGPS", "LibOld", "UsesOld", "LoopA", "LoopB", "Selfish", "Plain"} {
+		graph.Nodes[key] = &eso.GraphNode{Key: key}
+	}
+	graph.Nodes["LibMissing"] = &eso.GraphNode{Key: "LibMissing", Missing: true}
+
+	graph.Edges = []eso.GraphEdge{
+		{From: "Top", To: "LibMid", Type: eso.RequiredEdge},
+		{From: "Top", To: "MyMap", Type: eso.OptionalEdge},
+		{From: "LibMid", To: "LibGPS", Type: eso.RequiredEdge},
+		{From: "MyMap", To: "LibGPS", Type: eso.RequiredEdge},
+		{From: "MyMap", To: "LibMissing", Type: eso.OptionalEdge},
+		{From: "UsesOld", To: "LibOld", Type: eso.RequiredEdge, Constraint: ">=5", Outdated: true},
+		{From: "LoopA", To: "LoopB", Type: eso.RequiredEdge},
+		{From: "LoopB", To: "LoopA", Type: eso.OptionalEdge},
+		{From: "Selfish", To: "Selfish", Type: eso.RequiredEdge},
+	}
+
+	return graph
+}
+
+func TestDependencyGraph_LoadOrder(t *testing.T) {
+	plan := loadOrderGraph().LoadOrder()
+
+	assert.Equal(t, []string{"LibGPS", "LibMid", "LibOld", "MyMap", "Plain", "Selfish", "Top"}, plan.Order)
+
+	keys := []string{}
+	for _, failure := range plan.Failures {
+		keys = append(keys, failure.Key)
+	}
+	assert.Equal(t, []string{"LoopA", "LoopB", "UsesOld"}, keys)
+
+	failure, failed := plan.Failure("LoopA")
+	require.True(t, failed)
+	assert.Equal(t, eso.LoadCircularDependency, failure.Reason)
+	assert.Equal(t, "circular dependency between LoopA, LoopB", failure.String())
+
+	failure, failed = plan.Failure("UsesOld")
+	require.True(t, failed)
+	assert.Equal(t, eso.LoadOutdatedDependency, failure.Reason)
+	assert.Equal(t, "requires LibOld >=5, but an older version is installed", failure.String())
+
+	_, failed = plan.Failure("MyMap")
+	assert.False(t, failed)
+}
+
+func TestDependencyGraph_LoadOrder_Cascades(t *testing.T) {
+	plan := loadOrderGraph().LoadOrder("LibGPS")
+
+	failure, failed := plan.Failure("LibGPS")
+	require.True(t, failed)
+	assert.Equal(t, eso.LoadDisabled, failure.Reason)
+	assert.Equal(t, "disabled", failure.String())
+
+	failure, failed = plan.Failure("LibMid")
+	require.True(t, failed)
+	assert.Equal(t, eso.LoadFailedDependency, failure.Reason)
+	assert.Equal(t, "LibGPS", failure.Cause)
+
+	failure, failed = plan.Failure("Top")
+	require.True(t, failed)
+	assert.Equal(t, "requires LibMid, which won't load", failure.String())
+	assert.Equal(t, "LibGPS", failure.Cause)
+
+	assert.NotContains(t, plan.Order, "MyMap")
+	assert.Equal(t, []string{"LibOld", "Plain", "Selfish"}, plan.Order)
+}
+
+func TestDependencyGraph_LoadOrder_MissingDependency(t *testing.T) {
+	graph := loadOrderGraph()
+	graph.Edges = append(graph.Edges, eso.GraphEdge{From: "Plain", To: "LibMissing", Type: eso.RequiredEdge})
+
+	plan := graph.LoadOrder("Unknown", "LibMissing")
+
+	failure, failed := plan.Failure("Plain")
+	require.True(t, failed)
+	assert.Equal(t, eso.LoadMissingDependency, failure.Reason)
+	assert.Equal(t, "requires LibMissing, which isn't installed", failure.String())
+
+	_, failed = plan.Failure("LibMissing")
+	assert.False(t, failed)
+}
+
+func TestLoadFailureReason_String(t *testing.T) {
+	assert.Equal(t, "disabled", eso.LoadDisabled.String())
+	assert.Equal(t, "missing dependency", eso.LoadMissingDependency.String())
+	assert.Equal(t, "outdated dependency", eso.LoadOutdatedDependency.String())
+	assert.Equal(t, "failed dependency", eso.LoadFailedDependency.String())
+	assert.Equal(t, "circular dependency", eso.LoadCircularDependency.String())
+}
@@ -0,0 +1,832 @@
+package eso
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/afero"
+)
+
+// LuaKind identifies the type of a LuaValue.
+type LuaKind int
+
+const (
+	LuaNil LuaKind = iota
+	LuaBoolean
+	LuaNumber
+	LuaString
+	LuaTable
+)
+
+// String returns the Lua name of the type.
+func (K LuaKind) String() string {
+	switch K {
+	case LuaNil:
+		return "nil"
+	case LuaBoolean:
+		return "boolean"
+	case LuaNumber:
+		return "number"
+	case LuaString:
+		return "string"
+	case LuaTable:
+		return "table"
+	default:
+		return "unknown"
+	}
+}
+
+// LuaPosition is a location within the source of a Lua file.
+type LuaPosition struct {
+	Offset int // Byte offset from the start of the file
+	Line   int // 1-based line number
+	Column int // 1-based column, in bytes
+}
+
+// String returns the position formatted as "line N, column N".
+func (P LuaPosition) String() string {
+	return fmt.Sprintf("line %d, column %d", P.Line, P.Column)
+}
+
+// LuaValue is a single value parsed from a Lua file: a scalar, or a table of fields.
+type LuaValue struct {
+	Kind   LuaKind
+	Bool   bool
+	Number float64
+	String string      // The decoded contents of a string
+	Raw    string      // The value exactly as written in the source, for scalars (e.g. "1.50" or "\"a\\nb\"")
+	Fields []*LuaField // The fields of a table, in source order
+	Start  LuaPosition
+	End    int // Byte offset just past the end of the value
+}
+
+// LuaField is a single entry within a Lua table, e.g. `["key"] = value,` or a positional `value,`.
+type LuaField struct {
+	Key        *LuaValue // nil for positional fields
+	Index      int       // 1-based index of positional fields, 0 for keyed fields
+	Identifier bool      // True if the key was written as a bare name (key = value) instead of ["key"] = value
+	Value      *LuaValue
+	Start      LuaPosition // Start of the key (or of the value, for positional fields)
+	End        int         // Byte offset just past the end of the value
+	After      int         // Byte offset just past the field's separator (comma or semicolon), or End if there isn't one
+}
+
+// LuaAssignment is a top-level `Name = value` statement, which is how each SavedVariables table is stored.
+type LuaAssignment struct {
+	Name  string
+	Value *LuaValue
+	Start LuaPosition
+	End   int // Byte offset just past the end of the value
+}
+
+// LuaDocument is a parsed Lua file, keeping the original source so that byte offsets can be used to edit it.
+type LuaDocument struct {
+	Source      []byte
+	Assignments []*LuaAssignment
+}
+
+// LuaSyntaxError describes where and why a Lua file couldn't be parsed.
+type LuaSyntaxError struct {
+	Position LuaPosition
+	Message  string
+}
+
+// Error returns the error formatted as "line N, column N: message".
+func (E LuaSyntaxError) Error() string {
+	return fmt.Sprintf("%s: %s", E.Position, E.Message)
+}
+
+// ReadLua reads and parses the Lua file at path.
+func ReadLua(AppFs afero.Fs, path string) (*LuaDocument, error) {
+	data, err := afero.ReadFile(AppFs, path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %q: %w", path, err)
+	}
+
+	document, err := ParseLua(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %q: %w", path, err)
+	}
+
+	return document, nil
+}
+
+// ParseLua parses the Lua table-literal format the game writes SavedVariables in: a series of `Name = value`
+// assignments, where values are strings, numbers, booleans, nil, or (nested) tables.
+func ParseLua(data []byte) (*LuaDocument, error) {
+	var parser = &luaParser{src: string(data), line: 1}
+	var document = &LuaDocument{Source: data}
+
+	if bytes.HasPrefix(data, byteOrderMarks[0]) {
+		parser.pos = len(byteOrderMarks[0])
+		parser.lineStart = parser.pos
+	}
+
+	for {
+		if err := parser.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		if parser.eof() {
+			return document, nil
+		}
+
+		assignment, err := parser.parseAssignment()
+		if err != nil {
+			return nil, err
+		}
+
+		document.Assignments = append(document.Assignments, assignment)
+	}
+}
+
+// ParseLuaValue parses a single Lua value, such as `true`, `42`, `"text"`, or `{ ["key"] = "value" }`.
+func ParseLuaValue(text string) (*LuaValue, error) {
+	var parser = &luaParser{src: text, line: 1}
+
+	if err := parser.skipSpace(); err != nil {
+		return nil, err
+	}
+
+	value, err := parser.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parser.skipSpace(); err != nil {
+		return nil, err
+	}
+
+	if !parser.eof() {
+		return nil, parser.errorf("unexpected %s after the value", parser.describe())
+	}
+
+	return value, nil
+}
+
+// Get returns the value assigned to the named variable, or nil if there isn't one.
+func (D LuaDocument) Get(name string) *LuaValue {
+	for _, assignment := range D.Assignments {
+		if assignment.Name == name {
+			return assignment.Value
+		}
+	}
+
+	return nil
+}
+
+// Names returns the names of every top-level variable, in source order.
+func (D LuaDocument) Names() []string {
+	names := []string{}
+
+	for _, assignment := range D.Assignments {
+		names = append(names, assignment.Name)
+	}
+
+	return names
+}
+
+// KeyString returns the field's key as a string: the contents of string keys, the source text of number keys,
+// or the index of positional fields.
+func (F LuaField) KeyString() string {
+	switch {
+	case F.Key == nil:
+		return strconv.Itoa(F.Index)
+	case F.Key.Kind == LuaString:
+		return F.Key.String
+	default:
+		return F.Key.Raw
+	}
+}
+
+// Field returns the field of a table with the given key (see LuaField.KeyString), or nil if there isn't one.
+func (V *LuaValue) Field(key string) *LuaField {
+	if V == nil || V.Kind != LuaTable {
+		return nil
+	}
+
+	for _, field := range V.Fields {
+		if field.KeyString() == key {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// Get returns the value of the table's field with the given key, or nil if there isn't one.
+func (V *LuaValue) Get(key string) *LuaValue {
+	if field := V.Field(key); field != nil {
+		return field.Value
+	}
+
+	return nil
+}
+
+// Keys returns the keys of every field of a table, in source order.
+func (V *LuaValue) Keys() []string {
+	keys := []string{}
+
+	if V == nil {
+		return keys
+	}
+
+	for _, field := range V.Fields {
+		keys = append(keys, field.KeyString())
+	}
+
+	return keys
+}
+
+type luaParser struct {
+	src       string
+	pos       int
+	line      int
+	lineStart int
+}
+
+func (P *luaParser) eof() bool {
+	return P.pos >= len(P.src)
+}
+
+func (P *luaParser) peek(offset int) byte {
+	if P.pos+offset >= len(P.src) {
+		return 0
+	}
+
+	return P.src[P.pos+offset]
+}
+
+func (P *luaParser) position() LuaPosition {
+	return LuaPosition{Offset: P.pos, Line: P.line, Column: P.pos - P.lineStart + 1}
+}
+
+func (P *luaParser) errorf(format string, args ...any) error {
+	return LuaSyntaxError{Position: P.position(), Message: fmt.Sprintf(format, args...)}
+}
+
+// Advances past a newline at the current position.
+func (P *luaParser) newline() {
+	P.pos++
+	P.line++
+	P.lineStart = P.pos
+}
+
+// Skips whitespace and comments.
+func (P *luaParser) skipSpace() error {
+	for !P.eof() {
+		switch P.src[P.pos] {
+		case '\n':
+			P.newline()
+		case ' ', '\t', '\r', '\v', '\f':
+			P.pos++
+		case '-':
+			if P.peek(1) != '-' {
+				return nil
+			}
+
+			P.pos += 2
+
+			if level := P.longBracketLevel(); level >= 0 {
+				if _, err := P.scanLongBracket(level); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for !P.eof() && P.src[P.pos] != '\n' {
+				P.pos++
+			}
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (P *luaParser) expect(c byte) error {
+	if P.peek(0) != c {
+		return P.errorf("expected %q, found %s", c, P.describe())
+	}
+
+	P.pos++
+
+	return nil
+}
+
+// Describes the character at the current position, for error messages.
+func (P *luaParser) describe() string {
+	if P.eof() {
+		return "end of file"
+	}
+
+	r, _ := utf8.DecodeRuneInString(P.src[P.pos:])
+
+	return strconv.QuoteRune(r)
+}
+
+func (P *luaParser) parseAssignment() (*LuaAssignment, error) {
+	start := P.position()
+
+	name := P.scanName()
+	if name == "" {
+		return nil, P.errorf("expected a variable name, found %s", P.describe())
+	}
+
+	if err := P.skipSpace(); err != nil {
+		return nil, err
+	}
+
+	if err := P.expect('='); err != nil {
+		return nil, err
+	}
+
+	if err := P.skipSpace(); err != nil {
+		return nil, err
+	}
+
+	value, err := P.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := &LuaAssignment{Name: name, Value: value, Start: start, End: value.End}
+
+	// Statements may optionally be separated by semicolons
+	if err := P.skipSpace(); err != nil {
+		return nil, err
+	}
+
+	if P.peek(0) == ';' {
+		P.pos++
+	}
+
+	return assignment, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
+
+// Scans an identifier at the current position, returning an empty string if there isn't one.
+func (P *luaParser) scanName() string {
+	start := P.pos
+
+	if !isNameStart(P.peek(0)) {
+		return ""
+	}
+
+	for !P.eof() && isNameChar(P.src[P.pos]) {
+		P.pos++
+	}
+
+	return P.src[start:P.pos]
+}
+
+func (P *luaParser) parseValue() (*LuaValue, error) {
+	var value *LuaValue
+	var err error
+	var start = P.position()
+
+	switch c := P.peek(0); {
+	case c == '{':
+		return P.parseTable()
+	case c == '"' || c == '\'':
+		value, err = P.parseString()
+	case c == '[' && P.longBracketLevel() >= 0:
+		value, err = P.parseLongString()
+	case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		value, err = P.parseNumber()
+	case isNameStart(c):
+		switch name := P.scanName(); name {
+		case "true", "false":
+			value = &LuaValue{Kind: LuaBoolean, Bool: name == "true"}
+		case "nil":
+			value = &LuaValue{Kind: LuaNil}
+		case "inf", "nan":
+			number, _ := parseLuaNumber(name)
+			value = &LuaValue{Kind: LuaNumber, Number: number}
+		default:
+			P.pos = start.Offset
+			return nil, P.errorf("unexpected name %q, expected a value", name)
+		}
+	default:
+		return nil, P.errorf("expected a value, found %s", P.describe())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	value.Start = start
+	value.End = P.pos
+	value.Raw = P.src[start.Offset:P.pos]
+
+	return value, nil
+}
+
+func (P *luaParser) parseTable() (*LuaValue, error) {
+	var table = &LuaValue{Kind: LuaTable, Start: P.position()}
+	var index int
+
+	P.pos++ // {
+
+	for {
+		if err := P.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		if P.eof() {
+			return nil, P.errorf("unfinished table starting at %s", table.Start)
+		}
+
+		if P.peek(0) == '}' {
+			P.pos++
+			table.End = P.pos
+			return table, nil
+		}
+
+		field, err := P.parseField()
+		if err != nil {
+			return nil, err
+		}
+
+		if field.Key == nil {
+			index++
+			field.Index = index
+		}
+
+		table.Fields = append(table.Fields, field)
+
+		if err := P.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		switch P.peek(0) {
+		case ',', ';':
+			P.pos++
+			field.After = P.pos
+		case '}':
+		default:
+			return nil, P.errorf("expected ',' or '}' after table field, found %s", P.describe())
+		}
+	}
+}
+
+func (P *luaParser) parseField() (*LuaField, error) {
+	var field = &LuaField{Start: P.position()}
+	var err error
+
+	switch {
+	case P.peek(0) == '[' && P.longBracketLevel() < 0:
+		P.pos++
+
+		if err = P.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		if field.Key, err = P.parseValue(); err != nil {
+			return nil, err
+		}
+
+		if field.Key.Kind == LuaNil || field.Key.Kind == LuaTable {
+			return nil, LuaSyntaxError{field.Key.Start, fmt.Sprintf("a %s can't be used as a table key", field.Key.Kind)}
+		}
+
+		if err = P.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		if err = P.expect(']'); err != nil {
+			return nil, err
+		}
+
+		if err = P.skipAssign(); err != nil {
+			return nil, err
+		}
+	case isNameStart(P.peek(0)):
+		// Either a `name = value` field, or a positional true/false/nil
+		keyStart := P.position()
+		name := P.scanName()
+
+		if err = P.skipSpace(); err != nil {
+			return nil, err
+		}
+
+		if P.peek(0) != '=' || P.peek(1) == '=' {
+			P.pos, P.line, P.lineStart = keyStart.Offset, keyStart.Line, keyStart.Offset-keyStart.Column+1
+			break
+		}
+
+		field.Key = &LuaValue{Kind: LuaString, String: name, Raw: name, Start: keyStart, End: keyStart.Offset + len(name)}
+		field.Identifier = true
+
+		if err = P.skipAssign(); err != nil {
+			return nil, err
+		}
+	}
+
+	if field.Value, err = P.parseValue(); err != nil {
+		return nil, err
+	}
+
+	field.End = field.Value.End
+	field.After = field.End
+
+	return field, nil
+}
+
+// Skips the `=` between a key and its value, along with any surrounding whitespace.
+func (P *luaParser) skipAssign() error {
+	if err := P.skipSpace(); err != nil {
+		return err
+	}
+
+	if err := P.expect('='); err != nil {
+		return err
+	}
+
+	return P.skipSpace()
+}
+
+func (P *luaParser) parseString() (*LuaValue, error) {
+	var quote = P.src[P.pos]
+	var output strings.Builder
+
+	P.pos++
+	start := P.pos
+
+	// Fast path for strings without escape sequences
+	for !P.eof() && P.src[P.pos] != quote && P.src[P.pos] != '\\' && P.src[P.pos] != '\n' {
+		P.pos++
+	}
+
+	if P.peek(0) == quote {
+		P.pos++
+		return &LuaValue{Kind: LuaString, String: P.src[start : P.pos-1]}, nil
+	}
+
+	output.WriteString(P.src[start:P.pos])
+
+	for {
+		if P.eof() || P.src[P.pos] == '\n' {
+			return nil, P.errorf("unfinished string")
+		}
+
+		c := P.src[P.pos]
+
+		switch c {
+		case quote:
+			P.pos++
+			return &LuaValue{Kind: LuaString, String: output.String()}, nil
+		case '\\':
+			if err := P.scanEscape(&output); err != nil {
+				return nil, err
+			}
+		default:
+			output.WriteByte(c)
+			P.pos++
+		}
+	}
+}
+
+var luaEscapes = map[byte]byte{
+	'a': '\a', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t', 'v': '\v', '\\': '\\', '"': '"', '\'': '\'',
+}
+
+// Decodes the escape sequence at the current position into output.
+func (P *luaParser) scanEscape(output *strings.Builder) error {
+	P.pos++ // \
+
+	if P.eof() {
+		return P.errorf("unfinished string")
+	}
+
+	c := P.src[P.pos]
+
+	if escaped, ok := luaEscapes[c]; ok {
+		output.WriteByte(escaped)
+		P.pos++
+		return nil
+	}
+
+	switch {
+	case c == '\n':
+		output.WriteByte('\n')
+		P.newline()
+	case c == '\r':
+		output.WriteByte('\n')
+		P.pos++
+		if P.peek(0) == '\n' {
+			P.newline()
+		}
+	case c >= '0' && c <= '9':
+		var value int
+		for i := 0; i < 3 && P.peek(0) >= '0' && P.peek(0) <= '9'; i++ {
+			value = value*10 + int(P.src[P.pos]-'0')
+			P.pos++
+		}
+
+		if value > 255 {
+			return P.errorf("decimal escape too large")
+		}
+
+		output.WriteByte(byte(value))
+	case c == 'x':
+		if P.pos+3 > len(P.src) {
+			return P.errorf("hexadecimal digit expected")
+		}
+
+		value, err := strconv.ParseUint(P.src[P.pos+1:P.pos+3], 16, 8)
+		if err != nil {
+			return P.errorf("hexadecimal digit expected")
+		}
+
+		output.WriteByte(byte(value))
+		P.pos += 3
+	case c == 'z':
+		P.pos++
+		for !P.eof() && strings.IndexByte(" \t\r\n\v\f", P.src[P.pos]) >= 0 {
+			if P.src[P.pos] == '\n' {
+				P.newline()
+			} else {
+				P.pos++
+			}
+		}
+	case c == 'u' && P.peek(1) == '{':
+		end := strings.IndexByte(P.src[P.pos:], '}')
+		if end < 0 {
+			return P.errorf("missing '}' in \\u{xxxx}")
+		}
+
+		value, err := strconv.ParseUint(P.src[P.pos+2:P.pos+end], 16, 32)
+		if err != nil {
+			return P.errorf("hexadecimal digit expected")
+		}
+
+		output.WriteRune(rune(value))
+		P.pos += end + 1
+	default:
+		return P.errorf("invalid escape sequence '\\%c'", c)
+	}
+
+	return nil
+}
+
+// Returns the level of the long bracket (e.g. [[ is 0, [==[ is 2) at the current position, or -1 if there isn't one.
+func (P *luaParser) longBracketLevel() int {
+	if P.peek(0) != '[' {
+		return -1
+	}
+
+	level := 0
+	for P.peek(level+1) == '=' {
+		level++
+	}
+
+	if P.peek(level+1) != '[' {
+		return -1
+	}
+
+	return level
+}
+
+// Scans a long bracket of the given level, returning its contents.
+// As in Lua, a newline immediately following the opening bracket is skipped.
+func (P *luaParser) scanLongBracket(level int) (string, error) {
+	var start = P.position()
+	var closing = "]" + strings.Repeat("=", level) + "]"
+
+	P.pos += level + 2
+
+	switch {
+	case P.peek(0) == '\r' && P.peek(1) == '\n':
+		P.pos++
+		P.newline()
+	case P.peek(0) == '\n':
+		P.newline()
+	}
+
+	contentStart := P.pos
+
+	end := strings.Index(P.src[P.pos:], closing)
+	if end < 0 {
+		return "", LuaSyntaxError{start, "unfinished long string or comment"}
+	}
+
+	for P.pos < contentStart+end {
+		if P.src[P.pos] == '\n' {
+			P.newline()
+		} else {
+			P.pos++
+		}
+	}
+
+	P.pos += len(closing)
+
+	return P.src[contentStart : contentStart+end], nil
+}
+
+func (P *luaParser) parseLongString() (*LuaValue, error) {
+	contents, err := P.scanLongBracket(P.longBracketLevel())
+	if err != nil {
+		return nil, err
+	}
+
+	return &LuaValue{Kind: LuaString, String: contents}, nil
+}
+
+// Parses a number, accepting everything the game may write: integers, decimals, exponents, hexadecimal,
+// and the infinity and NaN representations of both Lua ("inf", "nan") and the Windows C runtime ("1.#INF", "-1.#IND").
+func (P *luaParser) parseNumber() (*LuaValue, error) {
+	var start = P.pos
+
+	if P.peek(0) == '-' {
+		P.pos++
+	}
+
+	for !P.eof() {
+		c := P.src[P.pos]
+
+		if isNameChar(c) || c == '.' || c == '#' {
+			P.pos++
+			continue
+		}
+
+		// Signs are only part of the number when following an exponent
+		if (c == '+' || c == '-') && strings.IndexByte("eEpP", P.src[P.pos-1]) >= 0 && !isHexNumber(P.src[start:P.pos]) {
+			P.pos++
+			continue
+		}
+
+		break
+	}
+
+	text := P.src[start:P.pos]
+
+	number, ok := parseLuaNumber(text)
+	if !ok {
+		P.pos = start
+		return nil, P.errorf("malformed number %q", text)
+	}
+
+	return &LuaValue{Kind: LuaNumber, Number: number}, nil
+}
+
+func isHexNumber(text string) bool {
+	text = strings.TrimPrefix(text, "-")
+	return strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X")
+}
+
+func parseLuaNumber(text string) (float64, bool) {
+	var sign float64 = 1
+	var unsigned = strings.TrimPrefix(text, "-")
+
+	if unsigned != text {
+		sign = -1
+	}
+
+	if unsigned == "" {
+		return 0, false
+	}
+
+	if isHexNumber(unsigned) {
+		if value, err := strconv.ParseUint(unsigned[2:], 16, 64); err == nil {
+			return sign * float64(value), true
+		}
+
+		value, err := strconv.ParseFloat(unsigned, 64)
+		return sign * value, err == nil
+	}
+
+	if strings.ContainsAny(unsigned, "#nN") {
+		switch strings.ToUpper(unsigned) {
+		case "INF", "1.#INF":
+			return sign * math.Inf(1), true
+		case "NAN", "1.#IND", "1.#QNAN":
+			return math.NaN(), true
+		}
+	}
+
+	// Only accept what Lua would, so names like "Infinity" aren't treated as numbers
+	if c := unsigned[0]; c != '.' && (c < '0' || c > '9') {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(unsigned, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, false
+	}
+
+	return sign * value, true
+}
@@ -0,0 +1,122 @@
+package eso
+
+import (
+	"math"
+)
+
+// LuaChangeType identifies how a value differs between two Lua files.
+type LuaChangeType int
+
+const (
+	LuaAdded LuaChangeType = iota
+	LuaRemoved
+	LuaChanged
+)
+
+// String returns the name of the change type, e.g. "added".
+func (T LuaChangeType) String() string {
+	switch T {
+	case LuaAdded:
+		return "added"
+	case LuaRemoved:
+		return "removed"
+	case LuaChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// LuaChange is a single difference between two Lua values.
+type LuaChange struct {
+	Type LuaChangeType
+	Path []string  // Keys leading to the value, starting with the variable name when diffing documents
+	Old  *LuaValue // nil for added values
+	New  *LuaValue // nil for removed values
+}
+
+// PathString returns the path of the change, formatted as a query (see LuaDocument.Query).
+func (C LuaChange) PathString() string {
+	return FormatLuaPath(C.Path)
+}
+
+// DiffLuaDocuments returns every variable, or key within a variable, which was added, removed, or changed between two
+// documents.
+func DiffLuaDocuments(old, new *LuaDocument) []LuaChange {
+	return DiffLua(old.Table(), new.Table())
+}
+
+// DiffLua returns the structural differences between two values. Tables are compared key by key, reporting the
+// deepest keys which differ; tables are only reported as a whole when added, removed, or replaced by a scalar.
+// Changes are ordered as the keys appear in the old value, followed by keys which only appear in the new one.
+func DiffLua(old, new *LuaValue, path ...string) []LuaChange {
+	var changes []LuaChange
+
+	if old.Kind != LuaTable || new.Kind != LuaTable {
+		if !LuaEqual(old, new) {
+			changes = append(changes, LuaChange{Type: LuaChanged, Path: path, Old: old, New: new})
+		}
+
+		return changes
+	}
+
+	var seen = make(map[string]bool)
+
+	for _, field := range old.Fields {
+		var key = field.KeyString()
+		var fieldPath = append(path[:len(path):len(path)], key)
+
+		seen[key] = true
+
+		if newValue := new.Get(key); newValue != nil {
+			changes = append(changes, DiffLua(field.Value, newValue, fieldPath...)...)
+		} else {
+			changes = append(changes, LuaChange{Type: LuaRemoved, Path: fieldPath, Old: field.Value})
+		}
+	}
+
+	for _, field := range new.Fields {
+		var key = field.KeyString()
+
+		if !seen[key] {
+			changes = append(changes, LuaChange{Type: LuaAdded, Path: append(path[:len(path):len(path)], key), New: field.Value})
+		}
+	}
+
+	return changes
+}
+
+// LuaEqual returns true if two values are the same, ignoring formatting such as "1.50" vs "1.5" or the order of keys
+// within tables.
+func LuaEqual(a, b *LuaValue) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if a.Kind != b.Kind {
+		return false
+	}
+
+	switch a.Kind {
+	case LuaBoolean:
+		return a.Bool == b.Bool
+	case LuaNumber:
+		return a.Number == b.Number || (math.IsNaN(a.Number) && math.IsNaN(b.Number))
+	case LuaString:
+		return a.String == b.String
+	case LuaTable:
+		if len(a.Fields) != len(b.Fields) {
+			return false
+		}
+
+		for _, field := range a.Fields {
+			if !LuaEqual(field.Value, b.Get(field.KeyString())) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return true
+	}
+}
@@ -0,0 +1,106 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseLua(t *testing.T, source string) *eso.LuaDocument {
+	t.Helper()
+
+	document, err := eso.ParseLua([]byte(source))
+	require.NoError(t, err)
+
+	return document
+}
+
+func TestDiffLuaDocuments(t *testing.T) {
+	old := parseLua(t, `
+MyAddon_SV = {
+	["Default"] = {
+		["version"] = 3,
+		["scale"] = 0.8500000000,
+		["debug"] = false,
+		["position"] = { ["x"] = 10, ["y"] = 20 },
+		["favorites"] = { "Vivec City", "Wayrest" },
+	},
+}
+Removed_SV = { ["a"] = 1 }
+`)
+	new := parseLua(t, `
+MyAddon_SV = {
+	["Default"] = {
+		["version"] = 3.0,
+		["scale"] = 1,
+		["position"] = 5,
+		["favorites"] = { "Vivec City", "Rawl'kha", "Alinor" },
+		["added"] = { ["nested"] = true },
+	},
+}
+Added_SV = "new"
+`)
+
+	changes := eso.DiffLuaDocuments(old, new)
+
+	var summary []string
+	for _, change := range changes {
+		summary = append(summary, change.Type.String()+" "+change.PathString())
+	}
+
+	assert.Equal(t, []string{
+		"changed MyAddon_SV.Default.scale",
+		"removed MyAddon_SV.Default.debug",
+		"changed MyAddon_SV.Default.position",
+		"changed MyAddon_SV.Default.favorites.2",
+		"added MyAddon_SV.Default.favorites.3",
+		"added MyAddon_SV.Default.added",
+		"removed Removed_SV",
+		"added Added_SV",
+	}, summary)
+
+	assert.Equal(t, "0.8500000000", changes[0].Old.Raw)
+	assert.Equal(t, "1", changes[0].New.Raw)
+	assert.Nil(t, changes[1].New)
+	assert.Equal(t, eso.LuaTable, changes[2].Old.Kind)
+	assert.Equal(t, eso.LuaNumber, changes[2].New.Kind)
+	assert.Nil(t, changes[5].Old)
+	assert.True(t, changes[5].New.Get("nested").Bool)
+}
+
+func TestDiffLua_Identical(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	assert.Empty(t, eso.DiffLuaDocuments(document, parseSample(t, "MyAddon.lua")))
+}
+
+func TestDiffLua_Path(t *testing.T) {
+	old := parseLua(t, `SV = { ["a"] = { ["b"] = 1 } }`)
+	new := parseLua(t, `SV = { ["a"] = { ["b"] = 2 } }`)
+
+	changes := eso.DiffLua(old.Get("SV").Get("a"), new.Get("SV").Get("a"), "SV", "a")
+	require.Len(t, changes, 1)
+	assert.Equal(t, []string{"SV", "a", "b"}, changes[0].Path)
+}
+
+func TestLuaEqual(t *testing.T) {
+	document := parseLua(t, `
+SV = {
+	one = 1, oneFloat = 1.0, two = 2,
+	str = "1", nan = -1.#IND, nan2 = -1.#IND,
+	t1 = { a = 1, b = 2 }, t2 = { b = 2, a = 1 }, t3 = { a = 1 },
+	yes = true, no = false,
+}`).Get("SV")
+
+	assert.True(t, eso.LuaEqual(document.Get("one"), document.Get("oneFloat")))
+	assert.False(t, eso.LuaEqual(document.Get("one"), document.Get("two")))
+	assert.False(t, eso.LuaEqual(document.Get("one"), document.Get("str")))
+	assert.True(t, eso.LuaEqual(document.Get("nan"), document.Get("nan2")))
+	assert.True(t, eso.LuaEqual(document.Get("t1"), document.Get("t2")))
+	assert.False(t, eso.LuaEqual(document.Get("t1"), document.Get("t3")))
+	assert.False(t, eso.LuaEqual(document.Get("yes"), document.Get("no")))
+	assert.False(t, eso.LuaEqual(document.Get("yes"), nil))
+	assert.True(t, eso.LuaEqual(nil, nil))
+}
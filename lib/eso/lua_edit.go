@@ -0,0 +1,205 @@
+package eso
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LuaEdit replaces the bytes between Start and End of a document's source with Text.
+type LuaEdit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// Apply returns a copy of the document's source with the edits made, leaving every other byte untouched.
+// Edits may be given in any order, but must not overlap.
+func (D LuaDocument) Apply(edits ...LuaEdit) ([]byte, error) {
+	var sorted = append([]LuaEdit{}, edits...)
+	var output = make([]byte, 0, len(D.Source))
+	var pos = 0
+
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	for _, edit := range sorted {
+		if edit.Start < pos || edit.End < edit.Start || edit.End > len(D.Source) {
+			return nil, fmt.Errorf("invalid edit of bytes %d to %d, edits must not overlap", edit.Start, edit.End)
+		}
+
+		output = append(output, D.Source[pos:edit.Start]...)
+		output = append(output, edit.Text...)
+		pos = edit.End
+	}
+
+	return append(output, D.Source[pos:]...), nil
+}
+
+// DeleteField returns the edit which removes a field, along with its separator. If the field is on a line of its own
+// (as the game writes them), the whole line is removed, including its indentation and line ending.
+func (D LuaDocument) DeleteField(field *LuaField) LuaEdit {
+	var src = D.Source
+	var start, end = field.Start.Offset, field.After
+
+	var lineStart = start
+	for lineStart > 0 && (src[lineStart-1] == ' ' || src[lineStart-1] == '\t') {
+		lineStart--
+	}
+
+	var lineEnd = end
+	for lineEnd < len(src) && (src[lineEnd] == ' ' || src[lineEnd] == '\t') {
+		lineEnd++
+	}
+
+	if (lineStart == 0 || src[lineStart-1] == '\n') && (lineEnd == len(src) || src[lineEnd] == '\n' || src[lineEnd] == '\r') {
+		if lineEnd < len(src) && src[lineEnd] == '\r' {
+			lineEnd++
+		}
+
+		if lineEnd < len(src) && src[lineEnd] == '\n' {
+			lineEnd++
+		}
+
+		return LuaEdit{Start: lineStart, End: lineEnd}
+	}
+
+	// The field shares its line with others, so only remove it and any spaces following it
+	return LuaEdit{Start: start, End: lineEnd}
+}
+
+// ReplaceField returns the edit which replaces a field's value, writing the whole field as the game would (see
+// FormatLua). Level is the field's level of nesting, where 0 is a top-level variable (see LuaDocument.FieldAt).
+func (D LuaDocument) ReplaceField(field *LuaField, value *LuaValue, level int) LuaEdit {
+	if level == 0 {
+		text := strings.TrimSuffix(FormatLuaAssignment(field.KeyString(), value), "\n")
+		return LuaEdit{Start: field.Start.Offset, End: field.End, Text: D.withLineEndings(text)}
+	}
+
+	replacement := *field
+	replacement.Value = value
+
+	return LuaEdit{Start: field.Start.Offset, End: field.After, Text: D.withLineEndings(FormatLuaField(&replacement, level))}
+}
+
+// InsertField returns the edit which adds a field to the end of a table, written as the game would (see FormatLua).
+// Level is the new field's level of nesting, where the fields of a top-level variable are at level 1.
+func (D LuaDocument) InsertField(table *LuaValue, field *LuaField, level int) LuaEdit {
+	var src = D.Source
+	var closing = table.End - 1 // The table's closing brace
+
+	var lineStart = closing
+	for lineStart > 0 && (src[lineStart-1] == ' ' || src[lineStart-1] == '\t') {
+		lineStart--
+	}
+
+	if lineStart == 0 || src[lineStart-1] == '\n' {
+		text := D.withLineEndings(strings.Repeat(luaIndent, level) + FormatLuaField(field, level) + "\n")
+
+		// The last field needs a separator before the new one, if it was written without one (such as by hand)
+		if len(table.Fields) > 0 {
+			if last := table.Fields[len(table.Fields)-1]; last.After == last.End {
+				return LuaEdit{Start: last.End, End: lineStart, Text: "," + string(src[last.End:lineStart]) + text}
+			}
+		}
+
+		return LuaEdit{Start: lineStart, End: lineStart, Text: text}
+	}
+
+	// The table is written on a single line, so add the field just before its closing brace
+	var text = FormatLuaField(field, level) + " "
+
+	if len(table.Fields) > 0 {
+		if last := table.Fields[len(table.Fields)-1]; last.After == last.End {
+			text = ", " + text
+		}
+	} else if src[closing-1] != ' ' {
+		text = " " + text
+	}
+
+	return LuaEdit{Start: closing, End: closing, Text: D.withLineEndings(text)}
+}
+
+// Converts the line endings of text to match the document's, which are "\r\n" if it was saved on Windows
+func (D LuaDocument) withLineEndings(text string) string {
+	if bytes.Contains(D.Source, []byte("\r\n")) {
+		return strings.ReplaceAll(text, "\n", "\r\n")
+	}
+
+	return text
+}
+
+// SetAt returns the document's source with the value at the given keys (starting with the variable name) set to
+// value, written as the game would (see FormatLua). Missing tables along the way are created, and a missing variable
+// is added to the end of the file. Everything else is left exactly as it was.
+func (D LuaDocument) SetAt(keys []string, value *LuaValue) ([]byte, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	// Find the deepest table along the path which already exists
+	var table = D.Table()
+	var depth = 0
+
+	for ; depth < len(keys); depth++ {
+		field := table.Field(keys[depth])
+		if field == nil {
+			break
+		}
+
+		if depth == len(keys)-1 {
+			return D.Apply(D.ReplaceField(field, value, depth))
+		}
+
+		if field.Value.Kind != LuaTable {
+			return nil, fmt.Errorf("%s is a %s, not a table", FormatLuaPath(keys[:depth+1]), field.Value.Kind)
+		}
+
+		table = field.Value
+	}
+
+	// Wrap the value in a table for each missing key after the first
+	for i := len(keys) - 1; i > depth; i-- {
+		wrapper := &LuaValue{Kind: LuaTable}
+		wrapper.Fields = append(wrapper.Fields, &LuaField{Key: newLuaKey(keys[i], nil), Value: value})
+		value = wrapper
+	}
+
+	if depth == 0 {
+		var separator = ""
+		if len(D.Source) > 0 && D.Source[len(D.Source)-1] != '\n' {
+			separator = "\n"
+		}
+
+		text := D.withLineEndings(separator + FormatLuaAssignment(keys[0], value))
+		return D.Apply(LuaEdit{Start: len(D.Source), End: len(D.Source), Text: text})
+	}
+
+	return D.Apply(D.InsertField(table, &LuaField{Key: newLuaKey(keys[depth], table), Value: value}, depth))
+}
+
+// DeleteAt returns the document's source with the field at the given keys (starting with the variable name) removed,
+// or the whole variable if only its name is given. Everything else is left exactly as it was.
+func (D LuaDocument) DeleteAt(keys []string) ([]byte, error) {
+	field, err := D.FieldAt(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return D.Apply(D.DeleteField(field))
+}
+
+// Returns the key for a new field: a number if the key is an integer and the table already uses number keys (as
+// lists do), or a string otherwise
+func newLuaKey(key string, table *LuaValue) *LuaValue {
+	if number, err := strconv.Atoi(key); err == nil && table != nil {
+		for _, field := range table.Fields {
+			if field.Key == nil || field.Key.Kind == LuaNumber {
+				return &LuaValue{Kind: LuaNumber, Number: float64(number), Raw: key}
+			}
+		}
+	}
+
+	return NewLuaString(key)
+}
@@ -0,0 +1,204 @@
+package eso_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLuaDocument_Apply(t *testing.T) {
+	document := parseLua(t, `SV = { a = 1, b = 2 }`)
+
+	output, err := document.Apply(
+		eso.LuaEdit{Start: 18, End: 19, Text: "20"},
+		eso.LuaEdit{Start: 11, End: 12, Text: "10"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, `SV = { a = 10, b = 20 }`, string(output))
+
+	_, err = document.Apply(eso.LuaEdit{Start: 5, End: 12}, eso.LuaEdit{Start: 11, End: 19})
+	assert.Error(t, err)
+
+	output, err = document.Apply()
+	require.NoError(t, err)
+	assert.Equal(t, string(document.Source), string(output))
+}
+
+func TestLuaDocument_DeleteField(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	field, err := document.FieldAt("MyAddon_SavedVariables", "Default", "@dyoung522", "$AccountWide", "position")
+	require.NoError(t, err)
+
+	output, err := document.Apply(document.DeleteField(field))
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(output), `["position"]`)
+	assert.Contains(t, string(output), "                [\"scale\"] = 0.8500000000,\n                [\"favorites\"] = \n")
+
+	updated, err := eso.ParseLua(output)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"version", "enabled", "debug", "scale", "favorites"}, updated.Get("MyAddon_SavedVariables").Get("Default").Get("@dyoung522").Get("$AccountWide").Keys())
+	assert.Len(t, eso.DiffLuaDocuments(document, updated), 1)
+}
+
+func TestLuaDocument_DeleteFieldInline(t *testing.T) {
+	document := parseLua(t, `SV = { a = 1, b = 2, c = 3 }`)
+
+	tests := map[string]string{
+		"a": `SV = { b = 2, c = 3 }`,
+		"b": `SV = { a = 1, c = 3 }`,
+		"c": `SV = { a = 1, b = 2, }`,
+	}
+
+	for key, expected := range tests {
+		output, err := document.Apply(document.DeleteField(document.Get("SV").Field(key)))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(output), key)
+	}
+}
+
+func TestLuaDocument_DeleteFieldCRLF(t *testing.T) {
+	document := parseLua(t, "SV =\r\n{\r\n    [\"a\"] = 1,\r\n    [\"b\"] = 2,\r\n}\r\n")
+
+	output, err := document.Apply(document.DeleteField(document.Get("SV").Field("a")))
+	require.NoError(t, err)
+	assert.Equal(t, "SV =\r\n{\r\n    [\"b\"] = 2,\r\n}\r\n", string(output))
+}
+
+func TestLuaDocument_ReplaceField(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	field, err := document.FieldAt("MyAddon_SavedVariables", "Default", "@dyoung522", "$AccountWide", "position")
+	require.NoError(t, err)
+
+	output, err := document.Apply(document.ReplaceField(field, eso.NewLuaString("center"), 4))
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "                [\"scale\"] = 0.8500000000,\n                [\"position\"] = \"center\",\n                [\"favorites\"] = \n")
+
+	field, err = document.FieldAt("MyAddon_Globals")
+	require.NoError(t, err)
+
+	output, err = document.Apply(document.ReplaceField(field, &eso.LuaValue{Kind: eso.LuaBoolean, Bool: true}, 0))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(output), "MyAddon_Globals = true\n"))
+}
+
+func TestLuaDocument_InsertField(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	field, err := document.FieldAt("MyAddon_SavedVariables", "Default", "@dyoung522", "$AccountWide", "position")
+	require.NoError(t, err)
+
+	table := &eso.LuaValue{Kind: eso.LuaTable}
+	table.Set("enabled", &eso.LuaValue{Kind: eso.LuaBoolean, Bool: true})
+
+	output, err := document.Apply(document.InsertField(field.Value, &eso.LuaField{Key: eso.NewLuaString("z"), Value: table}, 5))
+	require.NoError(t, err)
+	assert.Contains(t, string(output), `
+                    ["y"] = 340,
+                    ["z"] = 
+                    {
+                        ["enabled"] = true,
+                    },
+                },
+`)
+
+	inline := parseLua(t, "SV = { a = 1 }\nEmpty = {}\r\n")
+
+	output, err = inline.Apply(inline.InsertField(inline.Get("SV"), &eso.LuaField{Key: eso.NewLuaString("b"), Value: eso.NewLuaString("x")}, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "SV = { a = 1 , [\"b\"] = \"x\", }\nEmpty = {}\r\n", string(output))
+
+	output, err = inline.Apply(inline.InsertField(inline.Get("Empty"), &eso.LuaField{Key: eso.NewLuaString("b"), Value: eso.NewLuaString("x")}, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "SV = { a = 1 }\nEmpty = { [\"b\"] = \"x\", }\r\n", string(output))
+}
+
+func TestLuaDocument_InsertFieldCRLF(t *testing.T) {
+	document := parseLua(t, "SV =\r\n{\r\n    [\"a\"] = 1,\r\n}\r\n")
+
+	table := &eso.LuaValue{Kind: eso.LuaTable}
+	table.Set("c", &eso.LuaValue{Kind: eso.LuaNumber, Number: 2})
+
+	output, err := document.Apply(document.InsertField(document.Get("SV"), &eso.LuaField{Key: eso.NewLuaString("b"), Value: table}, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "SV =\r\n{\r\n    [\"a\"] = 1,\r\n    [\"b\"] = \r\n    {\r\n        [\"c\"] = 2,\r\n    },\r\n}\r\n", string(output))
+}
+
+func TestLuaDocument_SetAt(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+	path := []string{"MyAddon_SavedVariables", "Default", "@dyoung522", "$AccountWide"}
+
+	output, err := document.SetAt(append(path, "scale"), &eso.LuaValue{Kind: eso.LuaNumber, Number: 1})
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(string(document.Source), `["scale"] = 0.8500000000,`, `["scale"] = 1,`, 1), string(output))
+
+	output, err = document.SetAt(append(path, "window", "locked"), &eso.LuaValue{Kind: eso.LuaBoolean, Bool: true})
+	require.NoError(t, err)
+	assert.Contains(t, string(output), `
+                },
+                ["window"] = 
+                {
+                    ["locked"] = true,
+                },
+            },
+`)
+
+	output, err = document.SetAt(append(path, "favorites", "4"), eso.NewLuaString("Rimmen"))
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "                    [3] = \"Alinor\",\n                    [4] = \"Rimmen\",\n")
+
+	output, err = document.SetAt([]string{"MyAddon_New", "count"}, &eso.LuaValue{Kind: eso.LuaNumber, Number: 2})
+	require.NoError(t, err)
+	assert.Equal(t, string(document.Source)+"MyAddon_New =\n{\n    [\"count\"] = 2,\n}\n", string(output))
+
+	updated, err := eso.ParseLua(output)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MyAddon_SavedVariables", "MyAddon_Globals", "MyAddon_New"}, updated.Names())
+
+	_, err = document.SetAt(append(path, "version", "major"), &eso.LuaValue{Kind: eso.LuaNumber, Number: 1})
+	assert.ErrorContains(t, err, "is a number, not a table")
+
+	_, err = document.SetAt(nil, &eso.LuaValue{Kind: eso.LuaNil})
+	assert.Error(t, err)
+}
+
+func TestLuaDocument_SetAtNoTrailingNewline(t *testing.T) {
+	document := parseLua(t, "SV =\r\n{\r\n}")
+
+	output, err := document.SetAt([]string{"Other"}, &eso.LuaValue{Kind: eso.LuaBoolean})
+	require.NoError(t, err)
+	assert.Equal(t, "SV =\r\n{\r\n}\r\nOther = false\r\n", string(output))
+}
+
+func TestLuaDocument_DeleteAt(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	output, err := document.DeleteAt([]string{"MyAddon_Globals", "motd"})
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(string(document.Source), "    [\"motd\"] = \"Welcome to |cFF0000Tamriel|r!\",\n", "", 1), string(output))
+
+	output, err = document.DeleteAt([]string{"MyAddon_Globals"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(output), "MyAddon_Globals")
+	assert.True(t, strings.HasSuffix(string(output), "    },\n}\n"))
+
+	_, err = document.DeleteAt([]string{"MyAddon_Globals", "missing"})
+	assert.ErrorContains(t, err, `has no key "missing"`)
+}
+
+func TestLuaDocument_InsertFieldWithoutSeparator(t *testing.T) {
+	document := parseLua(t, "X =\n{\n    [\"a\"] = 1\n}\n")
+
+	output, err := document.SetAt([]string{"X", "b"}, eso.NewLuaString("v"))
+	require.NoError(t, err)
+	assert.Equal(t, "X =\n{\n    [\"a\"] = 1,\n    [\"b\"] = \"v\",\n}\n", string(output))
+
+	updated, err := eso.ParseLua(output)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, updated.Get("X").Keys())
+}
@@ -0,0 +1,211 @@
+package eso
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Summary returns the value on a single line, for printing alongside its path: strings quoted, numbers and booleans
+// as they are, and tables as their number of fields, such as "{3 fields}".
+func (V *LuaValue) Summary() string {
+	switch V.Kind {
+	case LuaString:
+		return strconv.Quote(V.String)
+	case LuaNumber:
+		return V.Raw
+	case LuaBoolean:
+		return strconv.FormatBool(V.Bool)
+	case LuaTable:
+		return fmt.Sprintf("{%d %s}", len(V.Fields), Pluralize("field", len(V.Fields)))
+	default:
+		return "nil"
+	}
+}
+
+// IsArray returns true if the table's keys are exactly 1..n in order, as written by the game for Lua lists.
+// Empty tables are not considered arrays.
+func (V *LuaValue) IsArray() bool {
+	if V == nil || V.Kind != LuaTable || len(V.Fields) == 0 {
+		return false
+	}
+
+	for i, field := range V.Fields {
+		if field.KeyString() != strconv.Itoa(i+1) {
+			return false
+		}
+
+		if field.Key != nil && field.Key.Kind != LuaNumber {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ToJson returns the value as indented JSON. Tables become objects with their keys in source order, or arrays if
+// their keys are 1..n. Numbers are written as they appear in the source where possible, while infinity and NaN
+// (which JSON can't represent) are written as strings.
+func (V *LuaValue) ToJson() (string, error) {
+	var compact, output bytes.Buffer
+
+	writeJsonValue(&compact, V)
+
+	if err := json.Indent(&output, compact.Bytes(), "", "  "); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
+
+func writeJsonValue(output *bytes.Buffer, value *LuaValue) {
+	if value == nil {
+		output.WriteString("null")
+		return
+	}
+
+	switch value.Kind {
+	case LuaBoolean:
+		output.WriteString(strconv.FormatBool(value.Bool))
+	case LuaNumber:
+		output.WriteString(jsonNumber(value))
+	case LuaString:
+		writeJsonString(output, value.String)
+	case LuaTable:
+		if value.IsArray() {
+			output.WriteByte('[')
+
+			for i, field := range value.Fields {
+				if i > 0 {
+					output.WriteByte(',')
+				}
+
+				writeJsonValue(output, field.Value)
+			}
+
+			output.WriteByte(']')
+			return
+		}
+
+		output.WriteByte('{')
+
+		for i, field := range value.Fields {
+			if i > 0 {
+				output.WriteByte(',')
+			}
+
+			writeJsonString(output, field.KeyString())
+			output.WriteByte(':')
+			writeJsonValue(output, field.Value)
+		}
+
+		output.WriteByte('}')
+	default:
+		output.WriteString("null")
+	}
+}
+
+func writeJsonString(output *bytes.Buffer, s string) {
+	encoded, _ := json.Marshal(s) // Marshaling a string can't fail
+	output.Write(encoded)
+}
+
+func jsonNumber(value *LuaValue) string {
+	if math.IsInf(value.Number, 0) || math.IsNaN(value.Number) {
+		encoded, _ := json.Marshal(value.Raw)
+		return string(encoded)
+	}
+
+	if value.Raw != "" && json.Valid([]byte(value.Raw)) {
+		return value.Raw
+	}
+
+	return strconv.FormatFloat(value.Number, 'g', -1, 64)
+}
+
+// ToYAML returns the value as YAML, keeping the keys of each table in source order.
+func (V *LuaValue) ToYAML() (string, error) {
+	var output bytes.Buffer
+
+	encoder := yaml.NewEncoder(&output)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(yamlNode(V)); err != nil {
+		return "", err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	return output.String(), nil
+}
+
+func yamlNode(value *LuaValue) *yaml.Node {
+	if value == nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+
+	switch value.Kind {
+	case LuaBoolean:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(value.Bool)}
+	case LuaNumber:
+		return yamlNumber(value)
+	case LuaString:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value.String}
+	case LuaTable:
+		if value.IsArray() {
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+
+			for _, field := range value.Fields {
+				node.Content = append(node.Content, yamlNode(field.Value))
+			}
+
+			return node
+		}
+
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+
+		for _, field := range value.Fields {
+			var key *yaml.Node
+
+			switch {
+			case field.Key == nil:
+				key = yamlNumber(&LuaValue{Kind: LuaNumber, Number: float64(field.Index)})
+			case field.Key.Kind == LuaNumber:
+				key = yamlNumber(field.Key)
+			default:
+				key = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: field.KeyString()}
+			}
+
+			node.Content = append(node.Content, key, yamlNode(field.Value))
+		}
+
+		return node
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+func yamlNumber(value *LuaValue) *yaml.Node {
+	switch {
+	case math.IsInf(value.Number, 1):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: ".inf"}
+	case math.IsInf(value.Number, -1):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: "-.inf"}
+	case math.IsNaN(value.Number):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: ".nan"}
+	case value.Raw != "" && !isHexNumber(value.Raw) && !strings.ContainsAny(value.Raw, ".eE"):
+		// Integers are kept as written, since large ids lose precision as floats
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value.Raw}
+	case value.Number == math.Trunc(value.Number) && math.Abs(value.Number) < 1<<53 && (value.Raw == "" || isHexNumber(value.Raw)):
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(int64(value.Number), 10)}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: jsonNumber(value)}
+	}
+}
@@ -0,0 +1,121 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLuaValue_Summary(t *testing.T) {
+	document, err := eso.ParseLua([]byte(`SV = { "a", 1.50, true, { x = 1 }, {}, nil }`))
+	require.NoError(t, err)
+
+	var summaries []string
+	for _, field := range document.Get("SV").Fields {
+		summaries = append(summaries, field.Value.Summary())
+	}
+
+	assert.Equal(t, []string{`"a"`, "1.50", "true", "{1 field}", "{0 fields}", "nil"}, summaries)
+	assert.Equal(t, "{6 fields}", document.Get("SV").Summary())
+}
+
+func TestLuaValue_IsArray(t *testing.T) {
+	document, err := eso.ParseLua([]byte(`SV = { list = { "a", "b" }, keyed = { [1] = "a", [2] = "b" }, gap = { [1] = "a", [3] = "b" }, strings = { ["1"] = "a" }, empty = {} }`))
+	require.NoError(t, err)
+
+	table := document.Get("SV")
+	assert.True(t, table.Get("list").IsArray())
+	assert.True(t, table.Get("keyed").IsArray())
+	assert.False(t, table.Get("gap").IsArray())
+	assert.False(t, table.Get("strings").IsArray())
+	assert.False(t, table.Get("empty").IsArray())
+	assert.False(t, table.IsArray())
+}
+
+func TestLuaValue_ToJson(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	value, err := document.Query("MyAddon_SavedVariables.Default.@dyoung522.$AccountWide")
+	require.NoError(t, err)
+
+	output, err := value.ToJson()
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "version": 3,
+  "enabled": true,
+  "debug": false,
+  "scale": 0.8500000000,
+  "position": {
+    "x": -125.5000000000,
+    "y": 340
+  },
+  "favorites": [
+    "Vivec City",
+    "Wayrest",
+    "Alinor"
+  ]
+}`, output)
+}
+
+func TestLuaValue_ToJsonNumbers(t *testing.T) {
+	document := parseSample(t, "Numbers.lua")
+
+	output, err := document.Get("Numbers_SavedVariables").ToJson()
+	require.NoError(t, err)
+
+	assert.Contains(t, output, `"hex": 255`)
+	assert.Contains(t, output, `"inf": "1.#INF"`)
+	assert.Contains(t, output, `"nan": "-1.#IND"`)
+	assert.Contains(t, output, `"bigId": 8798292047123456789`)
+	assert.Contains(t, output, `"2.5": "two and a half"`)
+}
+
+func TestLuaValue_ToYAML(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	output, err := document.Table().ToYAML()
+	require.NoError(t, err)
+	assert.Equal(t, `MyAddon_SavedVariables:
+  Default:
+    '@dyoung522':
+      $AccountWide:
+        version: 3
+        enabled: true
+        debug: false
+        scale: 0.8500000000
+        position:
+          x: -125.5000000000
+          y: 340
+        favorites:
+          - Vivec City
+          - Wayrest
+          - Alinor
+      "8798292047123456":
+        version: 3
+        $LastCharacterName: Dovahkiin
+        enabled: true
+      "8798292047654321":
+        version: 3
+        $LastCharacterName: Ærøn the Swift
+        enabled: false
+MyAddon_Globals:
+  lastRun: 1727798400
+  motd: Welcome to |cFF0000Tamriel|r!
+`, output)
+}
+
+func TestLuaValue_ToYAMLNumbers(t *testing.T) {
+	document := parseSample(t, "Numbers.lua")
+
+	output, err := document.Get("Numbers_SavedVariables").ToYAML()
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "hex: 255\n")
+	assert.Contains(t, output, "inf: .inf\n")
+	assert.Contains(t, output, "negativeInf: -.inf\n")
+	assert.Contains(t, output, "nan: .nan\n")
+	assert.Contains(t, output, "bigId: 8798292047123456789\n")
+	assert.Contains(t, output, "2.5: two and a half\n")
+}
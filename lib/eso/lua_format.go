@@ -0,0 +1,205 @@
+package eso
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// luaIndent is the indentation the game writes SavedVariables with, per level of nesting.
+const luaIndent = "    "
+
+// FormatLua returns the value written as the game writes SavedVariables, nested at the given level of indentation
+// (the fields of a top-level variable are at level 1). Scalars parsed from a file are written exactly as they were.
+//
+//	{
+//	    ["key"] = "value",
+//	    ["table"] =
+//	    {
+//	        [1] = 0.5000000000,
+//	    },
+//	}
+func FormatLua(value *LuaValue, level int) string {
+	var output strings.Builder
+
+	writeLua(&output, value, level)
+
+	return output.String()
+}
+
+// FormatLuaField returns a field written as the game writes it, including its separator but not its indentation.
+func FormatLuaField(field *LuaField, level int) string {
+	var output strings.Builder
+
+	writeLuaField(&output, field, level)
+
+	return output.String()
+}
+
+// FormatLuaAssignment returns a top-level variable written as the game writes it, e.g. "Name =\n{\n...\n}\n".
+func FormatLuaAssignment(name string, value *LuaValue) string {
+	if value.Kind == LuaTable {
+		return name + " =\n" + FormatLua(value, 0) + "\n"
+	}
+
+	return name + " = " + FormatLua(value, 0) + "\n"
+}
+
+func writeLua(output *strings.Builder, value *LuaValue, level int) {
+	if value == nil {
+		output.WriteString("nil")
+		return
+	}
+
+	switch value.Kind {
+	case LuaBoolean:
+		output.WriteString(strconv.FormatBool(value.Bool))
+	case LuaNumber:
+		output.WriteString(FormatLuaNumber(value))
+	case LuaString:
+		if value.Raw != "" {
+			output.WriteString(value.Raw)
+		} else {
+			output.WriteString(QuoteLuaString(value.String))
+		}
+	case LuaTable:
+		var indent = strings.Repeat(luaIndent, level)
+
+		output.WriteString("{\n")
+
+		for _, field := range value.Fields {
+			output.WriteString(indent + luaIndent)
+			writeLuaField(output, field, level+1)
+			output.WriteString("\n")
+		}
+
+		output.WriteString(indent + "}")
+	default:
+		output.WriteString("nil")
+	}
+}
+
+func writeLuaField(output *strings.Builder, field *LuaField, level int) {
+	switch {
+	case field.Key == nil:
+		output.WriteString("[" + strconv.Itoa(field.Index) + "]")
+	case field.Identifier:
+		output.WriteString(field.Key.String)
+	default:
+		output.WriteString("[")
+		writeLua(output, field.Key, level)
+		output.WriteString("]")
+	}
+
+	if field.Value.Kind == LuaTable {
+		// The game leaves a trailing space after the equals sign, and opens the table on the next line
+		output.WriteString(" = \n" + strings.Repeat(luaIndent, level))
+	} else {
+		output.WriteString(" = ")
+	}
+
+	writeLua(output, field.Value, level)
+	output.WriteString(",")
+}
+
+// FormatLuaNumber returns a number as written in the source, or as the game would write it: integers as they are,
+// and other numbers with 10 decimal places.
+func FormatLuaNumber(value *LuaValue) string {
+	switch {
+	case value.Raw != "":
+		return value.Raw
+	case math.IsInf(value.Number, 1):
+		return "1.#INF"
+	case math.IsInf(value.Number, -1):
+		return "-1.#INF"
+	case math.IsNaN(value.Number):
+		return "-1.#IND"
+	case value.Number == math.Trunc(value.Number) && math.Abs(value.Number) < 1<<53:
+		return strconv.FormatInt(int64(value.Number), 10)
+	default:
+		return strconv.FormatFloat(value.Number, 'f', 10, 64)
+	}
+}
+
+// QuoteLuaString returns s as a double-quoted Lua string, escaping quotes, backslashes, and control characters.
+func QuoteLuaString(s string) string {
+	var output strings.Builder
+
+	output.WriteByte('"')
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\':
+			output.WriteByte('\\')
+			output.WriteByte(c)
+		case '\n':
+			output.WriteString(`\n`)
+		case '\r':
+			output.WriteString(`\r`)
+		case '\t':
+			output.WriteString(`\t`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				// Pad to three digits, so a following digit isn't read as part of the escape
+				output.WriteString(fmt.Sprintf(`\%03d`, c))
+			} else {
+				output.WriteByte(c)
+			}
+		}
+	}
+
+	output.WriteByte('"')
+
+	return output.String()
+}
+
+// NewLuaString returns a string value, as if it were parsed from a file.
+func NewLuaString(s string) *LuaValue {
+	return &LuaValue{Kind: LuaString, String: s, Raw: QuoteLuaString(s)}
+}
+
+// Clone returns a deep copy of the value, which may be modified without affecting the original.
+func (V *LuaValue) Clone() *LuaValue {
+	if V == nil {
+		return nil
+	}
+
+	clone := *V
+	clone.Fields = nil
+
+	for _, field := range V.Fields {
+		fieldClone := *field
+		fieldClone.Key = field.Key.Clone()
+		fieldClone.Value = field.Value.Clone()
+		clone.Fields = append(clone.Fields, &fieldClone)
+	}
+
+	return &clone
+}
+
+// Set sets the value of a table's field, replacing its value if the key exists, or adding a new field otherwise.
+func (V *LuaValue) Set(key string, value *LuaValue) {
+	if field := V.Field(key); field != nil {
+		field.Value = value
+		return
+	}
+
+	V.Fields = append(V.Fields, &LuaField{Key: NewLuaString(key), Value: value})
+}
+
+// Delete removes a table's field, returning true if there was one.
+func (V *LuaValue) Delete(key string) bool {
+	if V == nil {
+		return false
+	}
+
+	for i, field := range V.Fields {
+		if field.KeyString() == key {
+			V.Fields = append(V.Fields[:i:i], V.Fields[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
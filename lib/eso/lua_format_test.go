@@ -0,0 +1,103 @@
+package eso_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatLua_RoundTrip(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	var output string
+	for _, assignment := range document.Assignments {
+		output += eso.FormatLuaAssignment(assignment.Name, assignment.Value)
+	}
+
+	// The sample is written exactly as the game writes SavedVariables
+	assert.Equal(t, string(document.Source), output)
+}
+
+func TestFormatLua(t *testing.T) {
+	value := &eso.LuaValue{Kind: eso.LuaTable}
+	value.Set("name", eso.NewLuaString("a \"quoted\"\nline"))
+	value.Set("enabled", &eso.LuaValue{Kind: eso.LuaBoolean, Bool: true})
+	value.Set("list", &eso.LuaValue{Kind: eso.LuaTable, Fields: []*eso.LuaField{
+		{Index: 1, Value: &eso.LuaValue{Kind: eso.LuaNumber, Number: 1}},
+		{Index: 2, Value: &eso.LuaValue{Kind: eso.LuaNumber, Number: 0.5}},
+	}})
+
+	assert.Equal(t, `{
+    ["name"] = "a \"quoted\"\nline",
+    ["enabled"] = true,
+    ["list"] = 
+    {
+        [1] = 1,
+        [2] = 0.5000000000,
+    },
+}`, eso.FormatLua(value, 0))
+
+	assert.Equal(t, "    {\n    }", "    "+eso.FormatLua(&eso.LuaValue{Kind: eso.LuaTable}, 1))
+}
+
+func TestFormatLua_Parses(t *testing.T) {
+	for _, name := range []string{"MyAddon.lua", "Escapes.lua", "Numbers.lua", "Lenient.lua"} {
+		document := parseSample(t, name)
+
+		var output string
+		for _, assignment := range document.Assignments {
+			output += eso.FormatLuaAssignment(assignment.Name, assignment.Value)
+		}
+
+		reparsed, err := eso.ParseLua([]byte(output))
+		require.NoError(t, err, name)
+		assert.Empty(t, eso.DiffLuaDocuments(document, reparsed), name)
+	}
+}
+
+func TestFormatLuaNumber(t *testing.T) {
+	assert.Equal(t, "1.50", eso.FormatLuaNumber(&eso.LuaValue{Kind: eso.LuaNumber, Number: 1.5, Raw: "1.50"}))
+	assert.Equal(t, "42", eso.FormatLuaNumber(&eso.LuaValue{Kind: eso.LuaNumber, Number: 42}))
+	assert.Equal(t, "-0.2500000000", eso.FormatLuaNumber(&eso.LuaValue{Kind: eso.LuaNumber, Number: -0.25}))
+	assert.Equal(t, "1.#INF", eso.FormatLuaNumber(&eso.LuaValue{Kind: eso.LuaNumber, Number: math.Inf(1)}))
+	assert.Equal(t, "-1.#INF", eso.FormatLuaNumber(&eso.LuaValue{Kind: eso.LuaNumber, Number: math.Inf(-1)}))
+	assert.Equal(t, "-1.#IND", eso.FormatLuaNumber(&eso.LuaValue{Kind: eso.LuaNumber, Number: math.NaN()}))
+}
+
+func TestQuoteLuaString(t *testing.T) {
+	tests := map[string]string{
+		"plain":          `"plain"`,
+		`say "hi"`:       `"say \"hi\""`,
+		`back\slash`:     `"back\\slash"`,
+		"tab\tnew\nline": `"tab\tnew\nline"`,
+		"bell\a1":        `"bell\0071"`,
+		"Ærøn":           `"Ærøn"`,
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, eso.QuoteLuaString(input), input)
+
+		document, err := eso.ParseLua([]byte("SV = " + expected))
+		require.NoError(t, err, input)
+		assert.Equal(t, input, document.Get("SV").String, input)
+	}
+}
+
+func TestLuaValue_CloneSetDelete(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+	original := document.Get("MyAddon_Globals")
+
+	clone := original.Clone()
+	clone.Set("motd", eso.NewLuaString("changed"))
+	clone.Set("added", eso.NewLuaString("new"))
+	assert.True(t, clone.Delete("lastRun"))
+	assert.False(t, clone.Delete("lastRun"))
+
+	assert.Equal(t, []string{"motd", "added"}, clone.Keys())
+	assert.Equal(t, "changed", clone.Get("motd").String)
+	assert.Equal(t, []string{"lastRun", "motd"}, original.Keys())
+	assert.Equal(t, "Welcome to |cFF0000Tamriel|r!", original.Get("motd").String)
+}
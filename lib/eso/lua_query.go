@@ -0,0 +1,159 @@
+package eso
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Table returns the document's top-level variables as a single table, keyed by variable name.
+// This allows a whole file to be queried and exported just like any other table.
+func (D LuaDocument) Table() *LuaValue {
+	table := &LuaValue{Kind: LuaTable}
+
+	for _, assignment := range D.Assignments {
+		table.Fields = append(table.Fields, &LuaField{
+			Key:   &LuaValue{Kind: LuaString, String: assignment.Name, Raw: assignment.Name, Start: assignment.Start},
+			Value: assignment.Value,
+			Start: assignment.Start,
+			End:   assignment.End,
+			After: assignment.End,
+		})
+	}
+
+	return table
+}
+
+// Query returns the value at the given path, e.g. "MyAddon_SV.Default.@Account.$AccountWide.settings".
+// The first element of the path is the variable name, followed by the keys of each nested table.
+// Keys containing dots may be written in brackets, e.g. `MyAddon_SV["key.with.dots"]` or `MyAddon_SV[2.5]`.
+func (D LuaDocument) Query(path string) (*LuaValue, error) {
+	keys, err := ParseLuaPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	field, err := D.FieldAt(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	return field.Value, nil
+}
+
+// FieldAt returns the field at the given keys, starting with the variable name. Top-level variables are returned as
+// fields spanning their whole assignment (see LuaDocument.Table).
+func (D LuaDocument) FieldAt(keys ...string) (*LuaField, error) {
+	var field *LuaField
+	var value = D.Table()
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	for i, key := range keys {
+		if value.Kind != LuaTable {
+			return nil, fmt.Errorf("%s is a %s, not a table", FormatLuaPath(keys[:i]), value.Kind)
+		}
+
+		if field = value.Field(key); field == nil {
+			if i == 0 {
+				return nil, fmt.Errorf("no variable named %q, expected one of: %s", key, strings.Join(value.Keys(), ", "))
+			}
+
+			return nil, fmt.Errorf("%s has no key %q", FormatLuaPath(keys[:i]), key)
+		}
+
+		value = field.Value
+	}
+
+	return field, nil
+}
+
+// ParseLuaPath splits a path such as `MyAddon_SV.Default["@Account"][1]` into its keys.
+func ParseLuaPath(path string) ([]string, error) {
+	var keys []string
+	var current strings.Builder
+	var expectKey = true // True if a key must follow (at the start, or after a dot)
+
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '.':
+			if expectKey {
+				return nil, fmt.Errorf("invalid path %q: empty key at position %d", path, i+1)
+			}
+
+			if current.Len() > 0 {
+				keys = append(keys, current.String())
+				current.Reset()
+			}
+
+			expectKey = true
+		case '[':
+			if current.Len() > 0 {
+				keys = append(keys, current.String())
+				current.Reset()
+			}
+
+			// Quoted keys may contain brackets, so look for the closing bracket after the closing quote
+			var start = i + 1
+			if start < len(path) && (path[start] == '"' || path[start] == '\'') {
+				for start++; start < len(path) && path[start] != path[i+1]; start++ {
+					if path[start] == '\\' {
+						start++
+					}
+				}
+			}
+
+			end := strings.IndexByte(path[min(start, len(path)):], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ']'", path)
+			}
+			end += min(start, len(path))
+
+			key := path[i+1 : end]
+			if unquoted, err := strconv.Unquote(key); err == nil {
+				key = unquoted
+			} else if len(key) >= 2 && key[0] == '\'' && key[len(key)-1] == '\'' {
+				key = key[1 : len(key)-1]
+			}
+
+			keys = append(keys, key)
+			i = end
+			expectKey = false
+		default:
+			current.WriteByte(c)
+			expectKey = false
+		}
+	}
+
+	if expectKey {
+		return nil, fmt.Errorf("invalid path %q: empty key at position %d", path, len(path)+1)
+	}
+
+	if current.Len() > 0 {
+		keys = append(keys, current.String())
+	}
+
+	return keys, nil
+}
+
+// FormatLuaPath joins keys into a path, bracketing any keys which contain dots or brackets.
+func FormatLuaPath(keys []string) string {
+	var output strings.Builder
+
+	for i, key := range keys {
+		if key == "" || strings.ContainsAny(key, ".[]") {
+			output.WriteString("[" + strconv.Quote(key) + "]")
+			continue
+		}
+
+		if i > 0 {
+			output.WriteByte('.')
+		}
+
+		output.WriteString(key)
+	}
+
+	return output.String()
+}
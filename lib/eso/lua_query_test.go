@@ -0,0 +1,110 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLuaDocument_Table(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+	table := document.Table()
+
+	assert.Equal(t, eso.LuaTable, table.Kind)
+	assert.Equal(t, []string{"MyAddon_SavedVariables", "MyAddon_Globals"}, table.Keys())
+	assert.Same(t, document.Get("MyAddon_Globals"), table.Get("MyAddon_Globals"))
+}
+
+func TestLuaDocument_Query(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	value, err := document.Query("MyAddon_SavedVariables.Default.@dyoung522.$AccountWide.position")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, value.Keys())
+
+	value, err = document.Query(`MyAddon_SavedVariables["Default"]["@dyoung522"].8798292047654321.$LastCharacterName`)
+	require.NoError(t, err)
+	assert.Equal(t, "Ærøn the Swift", value.String)
+
+	value, err = document.Query("MyAddon_SavedVariables.Default.@dyoung522.$AccountWide.favorites[2]")
+	require.NoError(t, err)
+	assert.Equal(t, "Wayrest", value.String)
+
+	value, err = document.Query("MyAddon_Globals")
+	require.NoError(t, err)
+	assert.Same(t, document.Get("MyAddon_Globals"), value)
+}
+
+func TestLuaDocument_QueryErrors(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	_, err := document.Query("MyAddon_SV")
+	assert.EqualError(t, err, `no variable named "MyAddon_SV", expected one of: MyAddon_SavedVariables, MyAddon_Globals`)
+
+	_, err = document.Query("MyAddon_SavedVariables.Default.@someone")
+	assert.EqualError(t, err, `MyAddon_SavedVariables.Default has no key "@someone"`)
+
+	_, err = document.Query("MyAddon_Globals.lastRun.value")
+	assert.EqualError(t, err, "MyAddon_Globals.lastRun is a number, not a table")
+
+	_, err = document.Query("MyAddon_Globals..lastRun")
+	assert.Error(t, err)
+}
+
+func TestParseLuaPath(t *testing.T) {
+	tests := []struct {
+		path string
+		keys []string
+	}{
+		{"MyAddon_SV", []string{"MyAddon_SV"}},
+		{"MyAddon_SV.Default.@Account.$AccountWide", []string{"MyAddon_SV", "Default", "@Account", "$AccountWide"}},
+		{`MyAddon_SV["key.with.dots"].next`, []string{"MyAddon_SV", "key.with.dots", "next"}},
+		{"MyAddon_SV['single'][1][2.5]", []string{"MyAddon_SV", "single", "1", "2.5"}},
+	}
+
+	for _, test := range tests {
+		keys, err := eso.ParseLuaPath(test.path)
+		require.NoError(t, err, test.path)
+		assert.Equal(t, test.keys, keys, test.path)
+	}
+
+	for _, path := range []string{"", ".MyAddon_SV", "MyAddon_SV.", "MyAddon_SV..Default", "MyAddon_SV[\"open"} {
+		_, err := eso.ParseLuaPath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestFormatLuaPath(t *testing.T) {
+	assert.Equal(t, "MyAddon_SV.Default.@Account", eso.FormatLuaPath([]string{"MyAddon_SV", "Default", "@Account"}))
+	assert.Equal(t, `MyAddon_SV["key.with.dots"].next`, eso.FormatLuaPath([]string{"MyAddon_SV", "key.with.dots", "next"}))
+
+	keys, err := eso.ParseLuaPath(eso.FormatLuaPath([]string{"a", "b.c", "[d]"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b.c", "[d]"}, keys)
+}
+
+func TestFindSavedVarsFile(t *testing.T) {
+	var fs = afero.NewMemMapFs()
+	viper.Set("eso_home", "/tmp/eso/Elder Scrolls Online")
+
+	const savedVarsPath = "/tmp/eso/Elder Scrolls Online/live/SavedVariables"
+	_ = afero.WriteFile(fs, savedVarsPath+"/MyAddon.lua", []byte("MyAddon_SV = {}\n"), 0644)
+	_ = afero.WriteFile(fs, "/tmp/elsewhere/Other.lua", []byte("Other_SV = {}\n"), 0644)
+
+	for _, name := range []string{"MyAddon", "MyAddon.lua", "myaddon", "MYADDON.LUA"} {
+		path, err := eso.FindSavedVarsFile(fs, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, savedVarsPath+"/MyAddon.lua", path, name)
+	}
+
+	path, err := eso.FindSavedVarsFile(fs, "/tmp/elsewhere/Other.lua")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/elsewhere/Other.lua", path)
+
+	_, err = eso.FindSavedVarsFile(fs, "Unknown")
+	assert.Error(t, err)
+}
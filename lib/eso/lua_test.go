@@ -0,0 +1,293 @@
+package eso_test
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseSample(t *testing.T, name string) *eso.LuaDocument {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "savedvars", name))
+	require.NoError(t, err)
+
+	document, err := eso.ParseLua(data)
+	require.NoError(t, err)
+
+	return document
+}
+
+func TestParseLua_SavedVariables(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	assert.Equal(t, []string{"MyAddon_SavedVariables", "MyAddon_Globals"}, document.Names())
+
+	account := document.Get("MyAddon_SavedVariables").Get("Default").Get("@dyoung522")
+	require.NotNil(t, account)
+	assert.Equal(t, []string{"$AccountWide", "8798292047123456", "8798292047654321"}, account.Keys())
+
+	accountWide := account.Get("$AccountWide")
+	assert.Equal(t, eso.LuaTable, accountWide.Kind)
+	assert.Equal(t, eso.LuaNumber, accountWide.Get("version").Kind)
+	assert.Equal(t, float64(3), accountWide.Get("version").Number)
+	assert.True(t, accountWide.Get("enabled").Bool)
+	assert.False(t, accountWide.Get("debug").Bool)
+	assert.Equal(t, 0.85, accountWide.Get("scale").Number)
+	assert.Equal(t, "0.8500000000", accountWide.Get("scale").Raw)
+	assert.Equal(t, -125.5, accountWide.Get("position").Get("x").Number)
+
+	favorites := accountWide.Get("favorites")
+	assert.Equal(t, []string{"1", "2", "3"}, favorites.Keys())
+	assert.Equal(t, "Wayrest", favorites.Get("2").String)
+
+	assert.Equal(t, "Ærøn the Swift", account.Get("8798292047654321").Get("$LastCharacterName").String)
+	assert.Equal(t, "Welcome to |cFF0000Tamriel|r!", document.Get("MyAddon_Globals").Get("motd").String)
+
+	assert.Nil(t, document.Get("Unknown"))
+	assert.Nil(t, accountWide.Get("unknown"))
+	assert.Nil(t, accountWide.Get("version").Get("anything"))
+}
+
+func TestParseLua_Positions(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+	source := string(document.Source)
+
+	assignment := document.Assignments[1]
+	assert.Equal(t, eso.LuaPosition{Offset: strings.Index(source, "MyAddon_Globals"), Line: 40, Column: 1}, assignment.Start)
+	assert.Equal(t, len(source)-1, assignment.End)
+
+	field := document.Get("MyAddon_SavedVariables").Get("Default").Get("@dyoung522").Get("$AccountWide").Field("version")
+	require.NotNil(t, field)
+	assert.Equal(t, 9, field.Start.Line)
+	assert.Equal(t, 17, field.Start.Column)
+	assert.Equal(t, `["version"] = 3`, source[field.Start.Offset:field.End])
+	assert.Equal(t, `["version"] = 3,`, source[field.Start.Offset:field.After])
+	assert.Equal(t, "3", source[field.Value.Start.Offset:field.Value.End])
+
+	table := document.Get("MyAddon_SavedVariables").Get("Default").Get("@dyoung522").Get("8798292047123456")
+	assert.Equal(t, 26, table.Start.Line)
+	assert.True(t, strings.HasPrefix(source[table.Start.Offset:table.End], "{"))
+	assert.True(t, strings.HasSuffix(source[table.Start.Offset:table.End], "}"))
+
+	defaults := document.Get("MyAddon_SavedVariables").Fields[0]
+	assert.Equal(t, defaults.End+1, defaults.After)
+	assert.Equal(t, "}", source[defaults.End-1:defaults.End])
+}
+
+func TestParseLua_Escapes(t *testing.T) {
+	table := parseSample(t, "Escapes.lua").Get("Escapes_SavedVariables")
+
+	assert.Equal(t, `She said "hello"`, table.Get("quote").String)
+	assert.Equal(t, `C:\Users\Public`, table.Get("backslash").String)
+	assert.Equal(t, "line one\nline two", table.Get("newline").String)
+	assert.Equal(t, "a\tb", table.Get("tab").String)
+	assert.Equal(t, "AB\x067", table.Get("decimal").String)
+	assert.Equal(t, "AB", table.Get("hex").String)
+	assert.Equal(t, "☺ Ærøskøbing", table.Get("unicode").String)
+	assert.Equal(t, "it's", table.Get("single").String)
+	assert.Equal(t, "first\nsecond", table.Get("continued").String)
+	assert.Equal(t, `raw \n text`, table.Get("long").String)
+	assert.Equal(t, "", table.Get("empty").String)
+	assert.Equal(t, `"She said \"hello\""`, table.Get("quote").Raw)
+
+	// Line numbers keep counting through strings spanning multiple lines
+	assert.Equal(t, 15, table.Field("empty").Start.Line)
+}
+
+func TestParseLua_Numbers(t *testing.T) {
+	table := parseSample(t, "Numbers.lua").Get("Numbers_SavedVariables")
+
+	assert.Equal(t, float64(42), table.Get("integer").Number)
+	assert.Equal(t, float64(-17), table.Get("negative").Number)
+	assert.Equal(t, 3.1415926536, table.Get("float").Number)
+	assert.Equal(t, 1.5e15, table.Get("exponent").Number)
+	assert.Equal(t, 2.5e-07, table.Get("smallExponent").Number)
+	assert.Equal(t, float64(255), table.Get("hex").Number)
+	assert.True(t, math.IsInf(table.Get("inf").Number, 1))
+	assert.True(t, math.IsInf(table.Get("negativeInf").Number, -1))
+	assert.True(t, math.IsNaN(table.Get("nan").Number))
+	assert.Equal(t, "8798292047123456789", table.Get("bigId").Raw)
+
+	// Numbers too large for a float64 overflow to infinity, as they do in Lua
+	overflow, err := eso.ParseLuaValue("{ 1e400, -1e400 }")
+	require.NoError(t, err)
+	assert.True(t, math.IsInf(overflow.Fields[0].Value.Number, 1))
+	assert.True(t, math.IsInf(overflow.Fields[1].Value.Number, -1))
+
+	assert.Equal(t, "one", table.Get("1").String)
+	assert.Equal(t, "two and a half", table.Get("2.5").String)
+	assert.Equal(t, "minus three", table.Get("-3").String)
+	assert.Equal(t, eso.LuaNumber, table.Field("1").Key.Kind)
+}
+
+func TestParseLua_Lenient(t *testing.T) {
+	document := parseSample(t, "Lenient.lua")
+
+	assert.Equal(t, []string{"Lenient_SavedVariables", "Second"}, document.Names())
+	assert.Equal(t, "plain string", document.Get("Second").String)
+
+	table := document.Get("Lenient_SavedVariables")
+	assert.Equal(t, "bare key", table.Get("name").String)
+	assert.True(t, table.Field("name").Identifier)
+	assert.False(t, table.Field("mixed").Identifier)
+
+	list := table.Get("list")
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, list.Keys())
+	assert.Equal(t, eso.LuaBoolean, list.Get("3").Kind)
+	assert.Equal(t, eso.LuaNil, list.Get("4").Kind)
+	assert.Nil(t, list.Field("1").Key)
+	assert.Equal(t, 1, list.Field("1").Index)
+
+	assert.Equal(t, []string{"1", "key", "2"}, table.Get("mixed").Keys())
+
+	// The last field of a table may not have a separator
+	last := list.Field("5")
+	assert.Equal(t, last.End, last.After)
+	assert.Equal(t, "3", string(document.Source[last.Start.Offset:last.After]))
+	assert.Empty(t, table.Get("nested").Get("deeper").Get("deepest").Fields)
+}
+
+func TestParseLua_LineEndings(t *testing.T) {
+	document, err := eso.ParseLua([]byte("\xEF\xBB\xBFVars =\r\n{\r\n    [\"a\"] = 1,\r\n    [\"b\"] = \"x\",\r\n}\r\n"))
+	require.NoError(t, err)
+
+	field := document.Get("Vars").Field("b")
+	assert.Equal(t, 4, field.Start.Line)
+	assert.Equal(t, 5, field.Start.Column)
+	assert.Equal(t, "x", field.Value.String)
+}
+
+func TestParseLua_Empty(t *testing.T) {
+	document, err := eso.ParseLua([]byte("  \n-- nothing here\n"))
+	require.NoError(t, err)
+	assert.Empty(t, document.Assignments)
+}
+
+func TestParseLua_Errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		line    int
+		column  int
+		message string
+	}{
+		{"Vars = {\n    [\"a\"] = 1\n    [\"b\"] = 2,\n}", 3, 5, "expected ',' or '}'"},
+		{"Vars = {", 1, 9, "unfinished table"},
+		{"Vars = \"abc\n\"", 1, 12, "unfinished string"},
+		{"Vars = \"\\q\"", 1, 10, "invalid escape sequence"},
+		{"Vars = \"\\300\"", 1, 13, "decimal escape too large"},
+		{"Vars = 12abc", 1, 8, "malformed number"},
+		{"Vars = something", 1, 8, "unexpected name"},
+		{"Vars = { [{}] = 1 }", 1, 11, "can't be used as a table key"},
+		{"Vars { }", 1, 6, "expected '='"},
+		{"= 1", 1, 1, "expected a variable name"},
+		{"Vars = [[never closed", 1, 8, "unfinished long string"},
+		{"Vars = { [\"a\"] 1 }", 1, 16, "expected '='"},
+		{"Vars = ", 1, 8, "expected a value, found end of file"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, err := eso.ParseLua([]byte(test.input))
+			require.Error(t, err)
+
+			var syntaxError eso.LuaSyntaxError
+			require.ErrorAs(t, err, &syntaxError)
+			assert.Equal(t, test.line, syntaxError.Position.Line)
+			assert.Equal(t, test.column, syntaxError.Position.Column)
+			assert.Contains(t, syntaxError.Message, test.message)
+			assert.Contains(t, err.Error(), fmt.Sprintf("line %d, column %d: ", test.line, test.column))
+		})
+	}
+}
+
+func TestParseLuaValue(t *testing.T) {
+	value, err := eso.ParseLuaValue(" 42 ")
+	require.NoError(t, err)
+	assert.Equal(t, eso.LuaNumber, value.Kind)
+	assert.Equal(t, 42.0, value.Number)
+
+	value, err = eso.ParseLuaValue(`"Vivec City"`)
+	require.NoError(t, err)
+	assert.Equal(t, "Vivec City", value.String)
+
+	value, err = eso.ParseLuaValue(`{ ["x"] = 1, y = true, "z" }`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x", "y", "1"}, value.Keys())
+
+	value, err = eso.ParseLuaValue("nil")
+	require.NoError(t, err)
+	assert.Equal(t, eso.LuaNil, value.Kind)
+
+	for _, input := range []string{"", "Vivec City", "1 2", "{"} {
+		_, err := eso.ParseLuaValue(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestReadLua(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	_ = afero.WriteFile(fs, "/sv/Good.lua", []byte("Good = { [\"a\"] = 1 }\n"), 0644)
+	_ = afero.WriteFile(fs, "/sv/Bad.lua", []byte("Bad = {"), 0644)
+
+	document, err := eso.ReadLua(fs, "/sv/Good.lua")
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), document.Get("Good").Get("a").Number)
+
+	_, err = eso.ReadLua(fs, "/sv/Bad.lua")
+	assert.ErrorContains(t, err, `error parsing "/sv/Bad.lua": line 1, column 8`)
+
+	_, err = eso.ReadLua(fs, "/sv/Missing.lua")
+	assert.ErrorContains(t, err, "error reading")
+}
+
+// Generates a SavedVariables file in the game's format, with the given number of characters.
+func generateSavedVars(characters int) []byte {
+	var output strings.Builder
+
+	output.WriteString("Big_SavedVariables =\n{\n    [\"Default\"] = \n    {\n        [\"@account\"] = \n        {\n")
+
+	for i := 0; i < characters; i++ {
+		fmt.Fprintf(&output, "            [\"%d\"] = \n            {\n", 8798292047000000+i)
+		fmt.Fprintf(&output, "                [\"$LastCharacterName\"] = \"Character \\\"%d\\\"\",\n", i)
+		for j := 0; j < 20; j++ {
+			fmt.Fprintf(&output, "                [\"setting%d\"] = %d.%d,\n", j, i, j)
+		}
+		output.WriteString("                [\"enabled\"] = true,\n            },\n")
+	}
+
+	output.WriteString("        },\n    },\n}\n")
+
+	return []byte(output.String())
+}
+
+func TestParseLua_Large(t *testing.T) {
+	data := generateSavedVars(5000)
+	require.Greater(t, len(data), 4*1024*1024)
+
+	document, err := eso.ParseLua(data)
+	require.NoError(t, err)
+
+	characters := document.Get("Big_SavedVariables").Get("Default").Get("@account")
+	assert.Len(t, characters.Fields, 5000)
+	assert.Equal(t, `Character "4999"`, characters.Get("8798292047004999").Get("$LastCharacterName").String)
+}
+
+func BenchmarkParseLua(b *testing.B) {
+	data := generateSavedVars(5000)
+	b.SetBytes(int64(len(data)))
+
+	for i := 0; i < b.N; i++ {
+		if _, err := eso.ParseLua(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package eso
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ManifestLineType identifies what a single line of an AddOn manifest contains.
+type ManifestLineType int
+
+const (
+	BlankLine     ManifestLineType = iota // Empty or whitespace-only line
+	CommentLine                           // Line starting with '#' or ';' that isn't a directive
+	DirectiveLine                         // "## Name: Value" line
+	FileLine                              // Lua or XML file to be loaded by the game
+)
+
+// String returns a human readable name for the line type.
+func (T ManifestLineType) String() string {
+	switch T {
+	case BlankLine:
+		return "blank"
+	case CommentLine:
+		return "comment"
+	case DirectiveLine:
+		return "directive"
+	case FileLine:
+		return "file"
+	default:
+		return "unknown"
+	}
+}
+
+// ManifestLine represents a single line of an AddOn manifest, exactly as it appears on disk.
+type ManifestLine struct {
+	Number    int              // 1-based line number within the manifest
+	Type      ManifestLineType // What this line contains
+	Text      string           // The line without its line ending
+	Ending    string           // The line ending ("\n", "\r\n" or "" for a final unterminated line)
+	Directive string           // Name of the directive (DirectiveLine only)
+	Value     string           // Raw directive value (DirectiveLine) or file path (FileLine)
+}
+
+// Manifest represents an ESO AddOn manifest file (e.g. MyAddon/MyAddon.txt).
+// It keeps every line, including comments, blank lines and unknown directives,
+// so that the original file can be reproduced byte-for-byte.
+type Manifest struct {
+	Path  string         // Path of the manifest, relative to the AddOns directory
+	BOM   []byte         // Byte order mark found at the start of the file, if any
+	Lines []ManifestLine // Every line of the manifest, in order
+}
+
+var (
+	directiveRE = regexp.MustCompile(`^##\s*(?P<Name>[\w-]+):(?P<Value>.*)$`)
+
+	byteOrderMarks = [][]byte{
+		{0xEF, 0xBB, 0xBF}, // UTF-8
+		{0xFF, 0xFE},       // UTF-16 (LE)
+		{0xFE, 0xFF},       // UTF-16 (BE)
+	}
+)
+
+// ParseManifest parses the raw contents of an AddOn manifest.
+// Parsing never fails; anything that isn't a directive, comment or blank line is treated as a file entry.
+func ParseManifest(data []byte) *Manifest {
+	manifest := &Manifest{}
+
+	for _, bom := range byteOrderMarks {
+		if bytes.HasPrefix(data, bom) {
+			manifest.BOM = append([]byte{}, bom...)
+			data = data[len(bom):]
+			break
+		}
+	}
+
+	for number := 1; len(data) > 0; number++ {
+		var text, ending string
+
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			text, ending = string(data[:i]), "\n"
+			data = data[i+1:]
+		} else {
+			text = string(data)
+			data = nil
+		}
+
+		if strings.HasSuffix(text, "\r") {
+			text, ending = strings.TrimSuffix(text, "\r"), "\r"+ending
+		}
+
+		manifest.Lines = append(manifest.Lines, parseManifestLine(number, text, ending))
+	}
+
+	return manifest
+}
+
+func parseManifestLine(number int, text string, ending string) ManifestLine {
+	line := ManifestLine{Number: number, Text: text, Ending: ending}
+	trimmed := strings.TrimSpace(text)
+
+	switch {
+	case trimmed == "":
+		line.Type = BlankLine
+	case directiveRE.MatchString(trimmed):
+		matches := directiveRE.FindStringSubmatch(trimmed)
+		line.Type = DirectiveLine
+		line.Directive = matches[directiveRE.SubexpIndex("Name")]
+		line.Value = matches[directiveRE.SubexpIndex("Value")]
+	case strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, ";"):
+		line.Type = CommentLine
+	default:
+		line.Type = FileLine
+		line.Value = trimmed
+	}
+
+	return line
+}
+
+// ReadManifest reads and parses the manifest located at path (relative to the AddOns directory).
+func ReadManifest(AppFs afero.Fs, path string) (*Manifest, error) {
+	data, err := afero.ReadFile(AppFs, filepath.Join(AddOnsPath(), path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	manifest := ParseManifest(data)
+	manifest.Path = path
+
+	return manifest, nil
+}
+
+// Bytes returns the manifest exactly as it would be written to disk.
+func (M Manifest) Bytes() []byte {
+	var buffer bytes.Buffer
+
+	_, _ = M.WriteTo(&buffer)
+
+	return buffer.Bytes()
+}
+
+// WriteTo writes the manifest to w, reproducing the original file byte-for-byte
+// (including the BOM and line endings) unless the lines have been modified.
+func (M Manifest) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+
+	n, err := w.Write(M.BOM)
+	total += int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	for _, line := range M.Lines {
+		n, err := io.WriteString(w, line.Text+line.Ending)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// Directives returns every directive line, in the order they appear.
+func (M Manifest) Directives() []ManifestLine {
+	return M.linesOfType(DirectiveLine)
+}
+
+// Comments returns every comment line, in the order they appear.
+func (M Manifest) Comments() []ManifestLine {
+	return M.linesOfType(CommentLine)
+}
+
+// FileLines returns every file line, in the order they appear.
+func (M Manifest) FileLines() []ManifestLine {
+	return M.linesOfType(FileLine)
+}
+
+// Files returns the ordered list of Lua/XML files the game would load for this manifest.
+func (M Manifest) Files() []string {
+	files := []string{}
+
+	for _, line := range M.FileLines() {
+		files = append(files, line.Value)
+	}
+
+	return files
+}
+
+// Has returns true if the directive appears at least once in the manifest.
+func (M Manifest) Has(name string) bool {
+	return len(M.GetAll(name)) > 0
+}
+
+// Get returns the cleaned value of the last occurrence of the named directive,
+// or an empty string if it doesn't appear in the manifest.
+func (M Manifest) Get(name string) string {
+	values := M.GetAll(name)
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
+
+// GetAll returns the cleaned value of every occurrence of the named directive, in order.
+func (M Manifest) GetAll(name string) []string {
+	values := []string{}
+
+	for _, line := range M.Directives() {
+		if line.Directive == name {
+			values = append(values, cleanString(line.Value))
+		}
+	}
+
+	return values
+}
+
+// Fields returns the space separated values of every occurrence of the named directive.
+// Repeated directives (e.g. multiple "## DependsOn:" lines) are accumulated in order.
+func (M Manifest) Fields(name string) []string {
+	fields := []string{}
+
+	for _, value := range M.GetAll(name) {
+		fields = append(fields, strings.Fields(value)...)
+	}
+
+	return fields
+}
+
+func (M Manifest) linesOfType(lineType ManifestLineType) []ManifestLine {
+	lines := []ManifestLine{}
+
+	for _, line := range M.Lines {
+		if line.Type == lineType {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,287 @@
+package eso
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// ManifestMaxLineLength is the maximum number of bytes the game reads from a single manifest line.
+// Anything beyond it is silently ignored, which usually truncates a directive or file name.
+const ManifestMaxLineLength = 301
+
+// KnownDirectives lists every manifest directive the game understands.
+var KnownDirectives = []string{
+	"Title",
+	"Author",
+	"Version",
+	"AddOnVersion",
+	"APIVersion",
+	"Description",
+	"Contributors",
+	"Credits",
+	"SavedVariables",
+	"DependsOn",
+	"OptionalDependsOn",
+	"PCDependsOn",
+	"PCOptionalDependsOn",
+	"ConsoleDependsOn",
+	"ConsoleOptionalDependsOn",
+	"IsLibrary",
+	"DisableSavedVariablesAutoSaving",
+}
+
+// Directives which are accepted by the game, but aren't the canonical spelling.
+var directiveAliases = map[string]string{
+	"AddonVersion": "AddOnVersion",
+}
+
+// IssueSeverity describes how serious a ManifestIssue is.
+type IssueSeverity int
+
+const (
+	IssueWarning IssueSeverity = iota // The AddOn will load, but probably not as the author intended
+	IssueError                        // The AddOn (or part of it) will fail to load
+)
+
+// String returns a human readable name for the severity.
+func (S IssueSeverity) String() string {
+	if S == IssueError {
+		return "error"
+	}
+
+	return "warning"
+}
+
+// ManifestIssue represents a single problem found while linting a manifest.
+type ManifestIssue struct {
+	Line     int // 1-based line number, or 0 if the issue applies to the whole file
+	Severity IssueSeverity
+	Message  string
+}
+
+// String returns the issue formatted as "line N: message", omitting the line for file-wide issues.
+func (I ManifestIssue) String() string {
+	if I.Line == 0 {
+		return I.Message
+	}
+
+	return fmt.Sprintf("line %d: %s", I.Line, I.Message)
+}
+
+// Lint checks the manifest for common mistakes which cause the game to silently ignore all or part of an AddOn.
+// Files listed in the manifest are looked up relative to the manifest's directory using the given filesystem.
+func (M Manifest) Lint(AppFs afero.Fs) []ManifestIssue {
+	var issues []ManifestIssue
+
+	issues = append(issues, M.lintEncoding()...)
+	issues = append(issues, M.lintLineEndings()...)
+
+	for _, line := range M.Lines {
+		if len(line.Text) > ManifestMaxLineLength {
+			issues = append(issues, ManifestIssue{line.Number, IssueError, fmt.Sprintf("line is %d bytes long, the game ignores anything past %d", len(line.Text), ManifestMaxLineLength)})
+		}
+	}
+
+	issues = append(issues, M.lintDirectives()...)
+	issues = append(issues, M.lintFiles(AppFs)...)
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+
+	return issues
+}
+
+func (M Manifest) lintEncoding() []ManifestIssue {
+	switch {
+	case bytes.Equal(M.BOM, byteOrderMarks[0]):
+		return []ManifestIssue{{0, IssueError, "file starts with a UTF-8 byte order mark (BOM), save it as UTF-8 without BOM"}}
+	case len(M.BOM) > 0:
+		return []ManifestIssue{{0, IssueError, "file is UTF-16 encoded, save it as UTF-8 without BOM"}}
+	}
+
+	for _, line := range M.Lines {
+		if strings.ContainsRune(line.Text, 0) {
+			return []ManifestIssue{{0, IssueError, "file contains NUL bytes, it is probably UTF-16 encoded, save it as UTF-8 without BOM"}}
+		}
+	}
+
+	return nil
+}
+
+func (M Manifest) lintLineEndings() []ManifestIssue {
+	var crlf, lf int
+
+	for _, line := range M.Lines {
+		switch line.Ending {
+		case "\r\n":
+			crlf++
+		case "\n":
+			lf++
+		}
+	}
+
+	if crlf > 0 && lf > 0 {
+		return []ManifestIssue{{0, IssueWarning, fmt.Sprintf("file has mixed line endings (%d CRLF, %d LF)", crlf, lf)}}
+	}
+
+	return nil
+}
+
+func (M Manifest) lintDirectives() []ManifestIssue {
+	var issues []ManifestIssue
+
+	if !M.Has("APIVersion") {
+		issues = append(issues, ManifestIssue{0, IssueError, "missing required APIVersion directive"})
+	}
+
+	for _, line := range M.Directives() {
+		if canonical, ok := directiveAliases[line.Directive]; ok {
+			issues = append(issues, ManifestIssue{line.Number, IssueWarning, fmt.Sprintf("directive %q should be spelled %q", line.Directive, canonical)})
+			continue
+		}
+
+		if !isKnownDirective(line.Directive) {
+			message := fmt.Sprintf("unknown directive %q", line.Directive)
+			if suggestion := suggestDirective(line.Directive); suggestion != "" {
+				message += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+
+			issues = append(issues, ManifestIssue{line.Number, IssueWarning, message})
+			continue
+		}
+
+		if strings.HasSuffix(line.Directive, "DependsOn") {
+			for _, entry := range strings.Fields(cleanString(line.Value)) {
+				if _, err := ParseDependency(entry); err != nil {
+					issues = append(issues, ManifestIssue{line.Number, IssueError, err.Error()})
+				}
+			}
+		}
+	}
+
+	return issues
+}
+
+func (M Manifest) lintFiles(AppFs afero.Fs) []ManifestIssue {
+	var issues []ManifestIssue
+
+	dir := filepath.Join(AddOnsPath(), filepath.Dir(M.Path))
+
+	for _, line := range M.FileLines() {
+		file := manifestFilePath(line.Value)
+
+		// Files depending on other runtime variables can't be checked
+		if strings.Contains(file, "$(") {
+			continue
+		}
+
+		if !fileExistsFold(AppFs, filepath.Join(dir, file)) {
+			issues = append(issues, ManifestIssue{line.Number, IssueError, fmt.Sprintf("file %q does not exist", line.Value)})
+		}
+	}
+
+	return issues
+}
+
+// Converts a file entry from a manifest into a relative OS path, substituting the default language.
+func manifestFilePath(entry string) string {
+	entry = strings.ReplaceAll(entry, "$(language)", "en")
+	return filepath.FromSlash(strings.ReplaceAll(entry, `\`, "/"))
+}
+
+// Returns true if the file exists, ignoring case (as the game does on Windows).
+func fileExistsFold(AppFs afero.Fs, path string) bool {
+	_, exists := resolvePathFold(AppFs, path)
+	return exists
+}
+
+// Returns the actual path of a file on disk, matching each path element without regard to case.
+func resolvePathFold(AppFs afero.Fs, path string) (string, bool) {
+	if exists, _ := afero.Exists(AppFs, path); exists {
+		return path, true
+	}
+
+	dir, name := filepath.Split(path)
+	dir = filepath.Clean(dir)
+
+	if dir == path {
+		return "", false
+	}
+
+	dir, exists := resolvePathFold(AppFs, dir)
+	if !exists {
+		return "", false
+	}
+
+	entries, err := afero.ReadDir(AppFs, dir)
+	if err != nil {
+		return "", false
+	}
+
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Name(), name) {
+			return filepath.Join(dir, entry.Name()), true
+		}
+	}
+
+	return "", false
+}
+
+func isKnownDirective(name string) bool {
+	for _, known := range KnownDirectives {
+		if name == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the known directive closest to name, or an empty string if none are close enough to be a typo.
+func suggestDirective(name string) string {
+	var suggestion string
+	var best = 3 // Maximum edit distance considered a typo
+
+	for _, known := range KnownDirectives {
+		if strings.EqualFold(name, known) {
+			return known
+		}
+
+		if distance := levenshtein(strings.ToLower(name), strings.ToLower(known)); distance < best {
+			suggestion, best = known, distance
+		}
+	}
+
+	return suggestion
+}
+
+// Returns the Levenshtein edit distance between two strings.
+func levenshtein(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,124 @@
+package eso_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintManifest(t *testing.T, data []byte, files ...string) []eso.ManifestIssue {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	viper.Set("eso_home", "/tmp/eso")
+
+	dir := filepath.Join(eso.AddOnsPath(), "MyAddon")
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, "MyAddon.txt"), data, 0644))
+
+	for _, file := range files {
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(dir, filepath.FromSlash(file)), []byte{}, 0644))
+	}
+
+	manifest, err := eso.ReadManifest(fs, filepath.Join("MyAddon", "MyAddon.txt"))
+	require.NoError(t, err)
+
+	return manifest.Lint(fs)
+}
+
+func issueMessages(issues []eso.ManifestIssue) []string {
+	messages := []string{}
+
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+
+	return messages
+}
+
+func TestManifestLint_Clean(t *testing.T) {
+	issues := lintManifest(t, []byte("## Title: My Addon\n## APIVersion: 101042\n## DependsOn: LibGPS>=71\nMyAddon.lua\nLang/$(language).lua\n"), "MyAddon.lua", "Lang/en.lua")
+
+	assert.Empty(t, issues)
+}
+
+func TestManifestLint_MissingFile(t *testing.T) {
+	issues := lintManifest(t, []byte("## APIVersion: 101042\nMyAddon.lua\nMissing.xml\n"), "MyAddon.lua")
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, eso.IssueError, issues[0].Severity)
+	assert.Equal(t, 3, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "Missing.xml")
+}
+
+func TestManifestLint_FilesAreCaseInsensitiveAndAcceptBackslashes(t *testing.T) {
+	issues := lintManifest(t, []byte("## APIVersion: 101042\nmyaddon.LUA\nmodules\\Module.lua\n"), "MyAddon.lua", "Modules/module.lua")
+
+	assert.Empty(t, issues)
+}
+
+func TestManifestLint_Encoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  []byte
+		expect string
+	}{
+		{name: "utf-8 BOM", input: []byte("\xEF\xBB\xBF## APIVersion: 101042\n"), expect: "UTF-8 byte order mark"},
+		{name: "utf-16 LE BOM", input: []byte("\xFF\xFE#\x00#\x00"), expect: "UTF-16"},
+		{name: "utf-16 without BOM", input: []byte("#\x00#\x00 \x00"), expect: "NUL bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := lintManifest(t, tt.input)
+
+			require.NotEmpty(t, issues)
+			assert.Equal(t, eso.IssueError, issues[0].Severity)
+			assert.Contains(t, issues[0].Message, tt.expect)
+		})
+	}
+}
+
+func TestManifestLint_MixedLineEndings(t *testing.T) {
+	mixed := lintManifest(t, []byte("## APIVersion: 101042\r\n## Title: Test\n"))
+	windows := lintManifest(t, []byte("## APIVersion: 101042\r\n## Title: Test\r\n"))
+
+	assert.Equal(t, []string{"file has mixed line endings (1 CRLF, 1 LF)"}, issueMessages(mixed))
+	assert.Empty(t, windows)
+}
+
+func TestManifestLint_LineLength(t *testing.T) {
+	long := "## Description: " + strings.Repeat("x", eso.ManifestMaxLineLength)
+	issues := lintManifest(t, []byte("## APIVersion: 101042\n"+long+"\n"))
+
+	require.Len(t, issues, 1)
+	assert.Equal(t, 2, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "the game ignores anything past")
+}
+
+func TestManifestLint_Directives(t *testing.T) {
+	issues := lintManifest(t, []byte("## Title: Test\n## DependOn: LibGPS\n## Athor: Me\n## AddonVersion: 1\n## Nonsense: 1\n"))
+
+	assert.Equal(t, []string{
+		"missing required APIVersion directive",
+		`line 2: unknown directive "DependOn", did you mean "DependsOn"?`,
+		`line 3: unknown directive "Athor", did you mean "Author"?`,
+		`line 4: directive "AddonVersion" should be spelled "AddOnVersion"`,
+		`line 5: unknown directive "Nonsense"`,
+	}, issueMessages(issues))
+}
+
+func TestManifestLint_VersionConstraints(t *testing.T) {
+	issues := lintManifest(t, []byte("## APIVersion: 101042\n## DependsOn: LibGPS>=71 LibMapPing>=abc\n## OptionalDependsOn: LibDebugLogger>263\n"))
+
+	require.Len(t, issues, 2)
+	assert.Equal(t, 2, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "LibMapPing>=abc")
+	assert.Equal(t, 3, issues[1].Line)
+	assert.Contains(t, issues[1].Message, "unsupported version constraint")
+}
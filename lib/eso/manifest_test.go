@@ -0,0 +1,133 @@
+package eso_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleManifest = `; This is a comment
+## Title: |c00FF00My|r Addon
+## Author: Author Name
+## Version: v1.2.3
+## AddOnVersion: 123
+## APIVersion: 101041 101042
+## SavedVariables: MyAddon_SV
+## DependsOn: LibAddonMenu-2.0>=35
+## DependsOn: LibGPS>=71
+## OptionalDependsOn: LibDebugLogger
+## X-Custom: something custom
+
+# Files
+MyAddon.lua
+Bindings.xml
+`
+
+func TestParseManifest_LineTypes(t *testing.T) {
+	manifest := eso.ParseManifest([]byte(sampleManifest))
+
+	require.Len(t, manifest.Lines, 15)
+
+	assert.Equal(t, eso.CommentLine, manifest.Lines[0].Type)
+	assert.Equal(t, eso.DirectiveLine, manifest.Lines[1].Type)
+	assert.Equal(t, eso.BlankLine, manifest.Lines[11].Type)
+	assert.Equal(t, eso.CommentLine, manifest.Lines[12].Type)
+	assert.Equal(t, eso.FileLine, manifest.Lines[13].Type)
+
+	assert.Equal(t, 14, manifest.Lines[13].Number)
+}
+
+func TestParseManifest_Directives(t *testing.T) {
+	manifest := eso.ParseManifest([]byte(sampleManifest))
+
+	assert.Len(t, manifest.Directives(), 10)
+	assert.Equal(t, "|c00FF00My|r Addon", manifest.Get("Title"))
+	assert.Equal(t, "something custom", manifest.Get("X-Custom"))
+	assert.Equal(t, "", manifest.Get("Missing"))
+	assert.False(t, manifest.Has("Missing"))
+	assert.Equal(t, []string{"LibAddonMenu-2.0>=35", "LibGPS>=71"}, manifest.Fields("DependsOn"))
+}
+
+func TestParseManifest_UnknownDirectivesAreKept(t *testing.T) {
+	manifest := eso.ParseManifest([]byte("## Title: Test\n## DependOn: LibGPS\n"))
+
+	directives := manifest.Directives()
+	require.Len(t, directives, 2)
+	assert.Equal(t, "DependOn", directives[1].Directive)
+	assert.Equal(t, 2, directives[1].Number)
+}
+
+func TestParseManifest_Files(t *testing.T) {
+	manifest := eso.ParseManifest([]byte(sampleManifest))
+
+	assert.Equal(t, []string{"MyAddon.lua", "Bindings.xml"}, manifest.Files())
+}
+
+func TestParseManifest_Empty(t *testing.T) {
+	manifest := eso.ParseManifest([]byte{})
+
+	assert.Empty(t, manifest.Lines)
+	assert.Empty(t, manifest.Bytes())
+}
+
+func TestManifest_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{name: "unix line endings", input: []byte(sampleManifest)},
+		{name: "windows line endings", input: []byte("## Title: Test\r\n## APIVersion: 101042\r\nTest.lua\r\n")},
+		{name: "mixed line endings", input: []byte("## Title: Test\r\n## APIVersion: 101042\nTest.lua\r\n")},
+		{name: "no trailing newline", input: []byte("## Title: Test\nTest.lua")},
+		{name: "utf-8 BOM", input: append([]byte{0xEF, 0xBB, 0xBF}, []byte("## Title: Test\nTest.lua\n")...)},
+		{name: "trailing whitespace", input: []byte("## Title: Test  \n  \t\n Test.lua \n\n")},
+		{name: "bare carriage return", input: []byte("## Title: Test\rTest.lua\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := eso.ParseManifest(tt.input)
+
+			var buffer bytes.Buffer
+			n, err := manifest.WriteTo(&buffer)
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.input)), n)
+			assert.Equal(t, tt.input, buffer.Bytes())
+			assert.Equal(t, tt.input, manifest.Bytes())
+		})
+	}
+}
+
+func TestManifest_BOM(t *testing.T) {
+	manifest := eso.ParseManifest(append([]byte{0xEF, 0xBB, 0xBF}, []byte("## Title: Test\n")...))
+
+	assert.Equal(t, []byte{0xEF, 0xBB, 0xBF}, manifest.BOM)
+	assert.Equal(t, "Test", manifest.Get("Title"))
+}
+
+func TestNewAddOnFromManifest(t *testing.T) {
+	manifest := eso.ParseManifest([]byte(sampleManifest))
+
+	addon, err := eso.NewAddOnFromManifest("MyAddon", manifest)
+	require.NoError(t, err)
+
+	assert.Equal(t, "MyAddon", addon.Key())
+	assert.Equal(t, "|c00FF00My|r Addon", addon.Title)
+	assert.Equal(t, "1.2.3", addon.Version)
+	assert.Equal(t, "123", addon.AddOnVersion)
+	assert.Equal(t, "101041 101042", addon.APIVersion)
+	assert.Equal(t, []string{"MyAddon_SV"}, addon.SavedVariables)
+	assert.Equal(t, []string{"LibAddonMenu-2.0>=35", "LibGPS>=71"}, addon.DependsOn)
+	assert.Equal(t, []string{"LibDebugLogger"}, addon.OptionalDependsOn)
+	assert.Same(t, manifest, addon.Manifest())
+}
+
+func TestNewAddOnFromManifest_EmptyKey(t *testing.T) {
+	_, err := eso.NewAddOnFromManifest("", eso.ParseManifest([]byte(sampleManifest)))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+package eso
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// LayoutProblem identifies how an AddOn was mis-extracted into the AddOns directory.
+type LayoutProblem int
+
+const (
+	NestedFolder  LayoutProblem = iota // AddOns/Foo/Foo/Foo.txt
+	RenamedFolder                      // AddOns/Foo-master/Foo.txt or AddOns/Foo 1.2/Foo.txt
+)
+
+// String returns a human readable description of the problem.
+func (P LayoutProblem) String() string {
+	switch P {
+	case NestedFolder:
+		return "nested in an extra folder"
+	case RenamedFolder:
+		return "folder name doesn't match the manifest"
+	default:
+		return "unknown"
+	}
+}
+
+// MisplacedAddOn describes an AddOn the game won't load, because it wasn't extracted
+// into the AddOns/<Name>/<Name>.txt layout the game expects.
+type MisplacedAddOn struct {
+	Problem  LayoutProblem
+	Dir      string // Directory containing the manifest, relative to the AddOns directory
+	Manifest string // Name of the manifest file
+	Target   string // Directory the AddOn should be in, relative to the AddOns directory
+	Conflict string // Reason the AddOn can't be fixed automatically, if any
+}
+
+// String returns a description of the problem and how it would be fixed.
+func (M MisplacedAddOn) String() string {
+	return fmt.Sprintf("%s (%s), should be %s", filepath.Join(M.Dir, M.Manifest), M.Problem, filepath.Join(M.Target, M.Manifest))
+}
+
+// Fixable returns true if the AddOn can be moved into place automatically.
+func (M MisplacedAddOn) Fixable() bool {
+	return M.Conflict == ""
+}
+
+// FindMisplacedAddOns scans the top level of the AddOns directory for folders which don't contain a matching
+// manifest, but do contain an AddOn manifest which the game would load if the folder were laid out correctly.
+func FindMisplacedAddOns(AppFs afero.Fs) ([]MisplacedAddOn, error) {
+	var misplaced []MisplacedAddOn
+	var addonsPath = AddOnsPath()
+
+	entries, err := afero.ReadDir(AppFs, addonsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error occurred while reading %q: %w", addonsPath, err)
+	}
+
+	installed := map[string]bool{}
+	for _, entry := range entries {
+		installed[entry.Name()] = true
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		dir := string(filepath.Separator) + entry.Name()
+
+		if findManifest(AppFs, dir, entry.Name()) != "" {
+			continue
+		}
+
+		if found, ok := findMisplacedAddOn(AppFs, dir); ok {
+			if found.Target != dir && found.Target != found.Dir && installed[strings.TrimPrefix(found.Target, string(filepath.Separator))] {
+				found.Conflict = fmt.Sprintf("%s already exists", found.Target)
+			}
+
+			misplaced = append(misplaced, found)
+		}
+	}
+
+	return misplaced, nil
+}
+
+// Looks for a manifest directly inside dir, or inside a single subdirectory of it.
+func findMisplacedAddOn(AppFs afero.Fs, dir string) (MisplacedAddOn, bool) {
+	if manifest := findManifest(AppFs, dir, ""); manifest != "" {
+		name := strings.TrimSuffix(manifest, filepath.Ext(manifest))
+		return MisplacedAddOn{Problem: RenamedFolder, Dir: dir, Manifest: manifest, Target: string(filepath.Separator) + name}, true
+	}
+
+	entries, err := afero.ReadDir(AppFs, filepath.Join(AddOnsPath(), dir))
+	if err != nil {
+		return MisplacedAddOn{}, false
+	}
+
+	var subdirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			subdirs = append(subdirs, entry.Name())
+		}
+	}
+
+	if len(subdirs) != 1 {
+		return MisplacedAddOn{}, false
+	}
+
+	subdir := filepath.Join(dir, subdirs[0])
+	if manifest := findManifest(AppFs, subdir, subdirs[0]); manifest != "" {
+		return MisplacedAddOn{Problem: NestedFolder, Dir: subdir, Manifest: manifest, Target: string(filepath.Separator) + subdirs[0]}, true
+	}
+
+	return MisplacedAddOn{}, false
+}
+
+// Returns the name of the highest precedence manifest within dir whose name matches name (ignoring the extension),
+// or any manifest if name is empty. Text files which don't contain any directives aren't considered manifests.
+func findManifest(AppFs afero.Fs, dir string, name string) string {
+	var candidates []string
+
+	entries, err := afero.ReadDir(AppFs, filepath.Join(AddOnsPath(), dir))
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		definition := AddOnDefinition{Name: entry.Name(), Dir: dir}
+
+		if entry.IsDir() || definition.Precedence() < 0 {
+			continue
+		}
+
+		if name != "" && definition.Key() != ToKey(name) {
+			continue
+		}
+
+		manifest, err := ReadManifest(AppFs, definition.Path())
+		if err != nil || len(manifest.Directives()) == 0 {
+			continue
+		}
+
+		candidates = append(candidates, entry.Name())
+	}
+
+	// When more than one manifest is found, only a single AddOn can be fixed automatically
+	if len(candidates) == 0 || (name == "" && len(distinctKeys(candidates)) > 1) {
+		return ""
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return manifestPrecedence(candidates[i]) < manifestPrecedence(candidates[j]) })
+
+	return candidates[0]
+}
+
+func distinctKeys(manifests []string) map[string]bool {
+	keys := map[string]bool{}
+
+	for _, manifest := range manifests {
+		keys[AddOnDefinition{Name: manifest}.Key()] = true
+	}
+
+	return keys
+}
+
+// Fix moves the AddOn into the AddOns/<Name>/<Name>.txt layout.
+// Nested folders have their contents moved up a level, renamed folders are renamed to match the manifest.
+func (M MisplacedAddOn) Fix(AppFs afero.Fs) error {
+	if !M.Fixable() {
+		return fmt.Errorf("cannot fix %s: %s", M.Dir, M.Conflict)
+	}
+
+	source := filepath.Join(AddOnsPath(), M.Dir)
+	target := filepath.Join(AddOnsPath(), M.Target)
+
+	// AddOns/Foo/Foo/Foo.txt: the AddOn is already below its target, so move its contents up
+	if filepath.Dir(source) == target {
+		return moveContentsUp(AppFs, source)
+	}
+
+	if err := AppFs.Rename(source, target); err != nil {
+		return fmt.Errorf("error moving %s to %s: %w", M.Dir, M.Target, err)
+	}
+
+	// Remove the folder it was extracted into, if nothing else was left in it
+	if parent := filepath.Dir(source); parent != AddOnsPath() {
+		if entries, err := afero.ReadDir(AppFs, parent); err == nil && len(entries) == 0 {
+			return AppFs.Remove(parent)
+		}
+	}
+
+	return nil
+}
+
+// Moves everything within dir into its parent, then removes dir.
+func moveContentsUp(AppFs afero.Fs, dir string) error {
+	parent := filepath.Dir(dir)
+
+	entries, err := afero.ReadDir(AppFs, dir)
+	if err != nil {
+		return fmt.Errorf("error occurred while reading %q: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if exists, _ := afero.Exists(AppFs, filepath.Join(parent, entry.Name())); exists {
+			return fmt.Errorf("cannot move %s up to %s, it already exists", entry.Name(), parent)
+		}
+	}
+
+	for _, entry := range entries {
+		if err := AppFs.Rename(filepath.Join(dir, entry.Name()), filepath.Join(parent, entry.Name())); err != nil {
+			return fmt.Errorf("error moving %s: %w", entry.Name(), err)
+		}
+	}
+
+	return AppFs.Remove(dir)
+}
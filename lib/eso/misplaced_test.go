@@ -0,0 +1,84 @@
+package eso_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindMisplacedAddOns(t *testing.T) {
+	fs := addOnsFs(t, map[string]string{
+		"AddOns/Good/Good.txt":           "## Title: Good\n",
+		"AddOns/Foo/Foo/Foo.txt":         "## Title: Foo\n",
+		"AddOns/Bar-master/Bar.txt":      "## Title: Bar\n",
+		"AddOns/Baz 1.2/Baz.addon":       "## Title: Baz\n",
+		"AddOns/Qux-master/Qux.txt":      "## Title: Qux\n",
+		"AddOns/Qux/Qux.txt":             "## Title: Qux\n",
+		"AddOns/NotAnAddon/readme.txt":   "Just some notes\n",
+		"AddOns/Multiple/One.txt":        "## Title: One\n",
+		"AddOns/Multiple/Two.txt":        "## Title: Two\n",
+		"AddOns/Zed-main/Zed/Zed.txt":    "## Title: Zed\n",
+		"AddOns/Zed-main/Zed/Zed.lua":    "",
+		"AddOns/Zed-main/Zed/README.txt": "",
+	})
+
+	misplaced, err := eso.FindMisplacedAddOns(fs)
+	require.NoError(t, err)
+	require.Len(t, misplaced, 5)
+
+	expected := []eso.MisplacedAddOn{
+		{Problem: eso.RenamedFolder, Dir: filepath.FromSlash("/Bar-master"), Manifest: "Bar.txt", Target: filepath.FromSlash("/Bar")},
+		{Problem: eso.RenamedFolder, Dir: filepath.FromSlash("/Baz 1.2"), Manifest: "Baz.addon", Target: filepath.FromSlash("/Baz")},
+		{Problem: eso.NestedFolder, Dir: filepath.FromSlash("/Foo/Foo"), Manifest: "Foo.txt", Target: filepath.FromSlash("/Foo")},
+		{Problem: eso.RenamedFolder, Dir: filepath.FromSlash("/Qux-master"), Manifest: "Qux.txt", Target: filepath.FromSlash("/Qux"), Conflict: filepath.FromSlash("/Qux") + " already exists"},
+		{Problem: eso.NestedFolder, Dir: filepath.FromSlash("/Zed-main/Zed"), Manifest: "Zed.txt", Target: filepath.FromSlash("/Zed")},
+	}
+
+	assert.Equal(t, expected, misplaced)
+	assert.False(t, misplaced[3].Fixable())
+}
+
+func TestMisplacedAddOn_Fix(t *testing.T) {
+	fs := addOnsFs(t, map[string]string{
+		"AddOns/Foo/Foo/Foo.txt":      "## Title: Foo\n",
+		"AddOns/Foo/Foo/Foo.lua":      "",
+		"AddOns/Bar-master/Bar.txt":   "## Title: Bar\n",
+		"AddOns/Zed-main/Zed/Zed.txt": "## Title: Zed\n",
+	})
+
+	misplaced, err := eso.FindMisplacedAddOns(fs)
+	require.NoError(t, err)
+	require.Len(t, misplaced, 3)
+
+	for _, addon := range misplaced {
+		require.NoError(t, addon.Fix(fs))
+	}
+
+	for _, path := range []string{"Foo/Foo.txt", "Foo/Foo.lua", "Bar/Bar.txt", "Zed/Zed.txt"} {
+		exists, _ := afero.Exists(fs, filepath.Join(eso.AddOnsPath(), filepath.FromSlash(path)))
+		assert.True(t, exists, "expected %s to exist", path)
+	}
+
+	for _, path := range []string{"Foo/Foo", "Bar-master", "Zed-main"} {
+		exists, _ := afero.Exists(fs, filepath.Join(eso.AddOnsPath(), filepath.FromSlash(path)))
+		assert.False(t, exists, "expected %s to be removed", path)
+	}
+
+	misplaced, err = eso.FindMisplacedAddOns(fs)
+	require.NoError(t, err)
+	assert.Empty(t, misplaced)
+
+	addons, errs := eso.GetAddOns(fs)
+	require.Empty(t, errs)
+	assert.Len(t, addons, 3)
+}
+
+func TestMisplacedAddOn_FixWithConflict(t *testing.T) {
+	addon := eso.MisplacedAddOn{Dir: "/Qux-master", Target: "/Qux", Conflict: "/Qux already exists"}
+
+	assert.Error(t, addon.Fix(afero.NewMemMapFs()))
+}
@@ -0,0 +1,139 @@
+package eso
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/afero"
+)
+
+// OrphanedAddOn describes an installed library which no other installed AddOn depends upon.
+type OrphanedAddOn struct {
+	Key           string
+	Dirs          []string // Every top-level installed copy, relative to the AddOns directory
+	Size          int64    // Total size of the installed copies, in bytes
+	UsedBy        []string // Other orphaned AddOns which depend upon this one, so it's only orphaned once they're removed
+	SavedVars     string   // Name of the AddOn's SavedVariables file, if one exists
+	SavedVarsSize int64    // Size of the SavedVariables file, in bytes
+}
+
+// IsLibraryName returns true if the key follows the "Lib" naming convention used by most libraries,
+// e.g. "LibAddonMenu-2.0" or "libCommonInventoryFilters", but not "Librarian".
+func IsLibraryName(key string) bool {
+	if len(key) < 3 || !strings.EqualFold(key[:3], "lib") {
+		return false
+	}
+
+	rest := []rune(key[3:])
+
+	return len(rest) == 0 || !unicode.IsLower(rest[0])
+}
+
+// Orphans returns every installed library (flagged with IsLibrary, or named like one) which no other installed AddOn
+// requires or optionally uses, sorted by key. Libraries which are only used by other orphans are included too,
+// since they'd be orphaned once those are removed. Libraries embedded within another AddOn are never orphaned.
+func (A AddOns) Orphans(AppFs afero.Fs) ([]OrphanedAddOn, error) {
+	var orphans []OrphanedAddOn
+	var graph = NewDependencyGraph(A)
+	var orphaned = map[string]bool{}
+
+	for _, key := range A.Keys() {
+		addon := A[key]
+		orphaned[key] = addon.Parent() == "" && (addon.IsLibrary() || IsLibraryName(key))
+	}
+
+	// Keep dropping libraries which are used by anything that isn't orphaned, until nothing changes
+	for changed := true; changed; {
+		changed = false
+
+		for key, candidate := range orphaned {
+			if !candidate {
+				continue
+			}
+
+			if A.usedOutside(graph, key, orphaned) {
+				orphaned[key], changed = false, true
+			}
+		}
+	}
+
+	for _, key := range A.Keys() {
+		if !orphaned[key] {
+			continue
+		}
+
+		orphan, err := newOrphanedAddOn(AppFs, A[key], graph)
+		if err != nil {
+			return nil, err
+		}
+
+		orphans = append(orphans, orphan)
+	}
+
+	return orphans, nil
+}
+
+// Returns true if the AddOn, or any AddOn embedded within it, is used by an AddOn which isn't orphaned
+// (and wouldn't be removed along with it).
+func (A AddOns) usedOutside(graph *DependencyGraph, key string, orphaned map[string]bool) bool {
+	for _, addon := range A {
+		if addon.Key() != key && !A.embeddedIn(addon.Key(), key) {
+			continue
+		}
+
+		for _, edge := range graph.DependentsOf(addon.Key()) {
+			if !orphaned[edge.From] && edge.From != key && !A.embeddedIn(edge.From, key) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Returns true if the AddOn is embedded (at any depth) within the directory of the parent AddOn.
+func (A AddOns) embeddedIn(key string, parent string) bool {
+	for current := A[key].Parent(); current != ""; current = A[current].Parent() {
+		if current == parent {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newOrphanedAddOn(AppFs afero.Fs, addon AddOn, graph *DependencyGraph) (OrphanedAddOn, error) {
+	orphan := OrphanedAddOn{Key: addon.Key()}
+
+	for _, instance := range addon.Instances() {
+		if instance.Parent() != "" {
+			continue
+		}
+
+		size, err := DiskUsage(AppFs, filepath.Join(AddOnsPath(), instance.Dir()))
+		if err != nil {
+			return orphan, fmt.Errorf("error occurred while reading %q: %w", instance.Dir(), err)
+		}
+
+		orphan.Dirs = append(orphan.Dirs, instance.Dir())
+		orphan.Size += size
+	}
+
+	for _, edge := range graph.DependentsOf(orphan.Key) {
+		if edge.From != orphan.Key {
+			orphan.UsedBy = append(orphan.UsedBy, edge.From)
+		}
+	}
+	sort.Strings(orphan.UsedBy)
+
+	savedVars := orphan.Key + ".lua"
+	if info, err := AppFs.Stat(filepath.Join(SavedVariablesPath(), savedVars)); err == nil {
+		orphan.SavedVars = savedVars
+		orphan.SavedVarsSize = info.Size()
+	}
+
+	return orphan, nil
+}
@@ -0,0 +1,77 @@
+package eso_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func orphanedAddOnsFs(t *testing.T) afero.Fs {
+	t.Helper()
+
+	return addOnsFs(t, map[string]string{
+		"AddOns/MyMap/MyMap.txt":                     "## Title: My Map\n## DependsOn: LibUsed\n## OptionalDependsOn: LibOptional\n",
+		"AddOns/LibUsed/LibUsed.txt":                 "## Title: LibUsed\n## IsLibrary: true\n",
+		"AddOns/LibOptional/LibOptional.txt":         "## Title: LibOptional\n",
+		"AddOns/LibDead/LibDead.txt":                 "## Title: LibDead\n## DependsOn: LibDeadDep LibDead\n",
+		"AddOns/LibDead/LibDead.lua":                 "-- 12 bytes",
+		"AddOns/LibDeadDep/LibDeadDep.txt":           "## Title: LibDeadDep\n## IsLibrary: true\n",
+		"AddOns/LibHost/LibHost.txt":                 "## Title: LibHost\n",
+		"AddOns/LibHost/LibEmbedded/LibEmbedded.txt": "## Title: LibEmbedded\n",
+		"AddOns/Other/Other.txt":                     "## Title: Other\n## DependsOn: LibEmbedded\n",
+		"AddOns/Helper/Helper.txt":                   "## Title: Helper\n## IsLibrary: true\n",
+		"AddOns/Librarian/Librarian.txt":             "## Title: Librarian\n",
+		"SavedVariables/LibDead.lua":                 "LibDead_SV = {}\n",
+	})
+}
+
+func TestAddOns_Orphans(t *testing.T) {
+	fs := orphanedAddOnsFs(t)
+
+	addons, errs := eso.GetAddOns(fs)
+	require.Empty(t, errs)
+
+	orphans, err := addons.Orphans(fs)
+	require.NoError(t, err)
+
+	keys := []string{}
+	for _, orphan := range orphans {
+		keys = append(keys, orphan.Key)
+	}
+	assert.Equal(t, []string{"Helper", "LibDead", "LibDeadDep"}, keys)
+
+	dead := orphans[1]
+	assert.Equal(t, []string{filepath.FromSlash("/LibDead")}, dead.Dirs)
+	assert.Equal(t, int64(len("## Title: LibDead\n## DependsOn: LibDeadDep LibDead\n")+len("-- 12 bytes")), dead.Size)
+	assert.Empty(t, dead.UsedBy)
+	assert.Equal(t, "LibDead.lua", dead.SavedVars)
+	assert.Equal(t, int64(len("LibDead_SV = {}\n")), dead.SavedVarsSize)
+
+	deadDep := orphans[2]
+	assert.Equal(t, []string{"LibDead"}, deadDep.UsedBy)
+	assert.Empty(t, deadDep.SavedVars)
+}
+
+func TestAddOns_Orphans_None(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	viper.Set("eso_home", "/tmp/eso")
+
+	orphans, err := eso.AddOns{}.Orphans(fs)
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+}
+
+func TestIsLibraryName(t *testing.T) {
+	assert.True(t, eso.IsLibraryName("LibAddonMenu-2.0"))
+	assert.True(t, eso.IsLibraryName("libCommonInventoryFilters"))
+	assert.True(t, eso.IsLibraryName("LIB3D"))
+	assert.True(t, eso.IsLibraryName("Lib"))
+	assert.False(t, eso.IsLibraryName("Librarian"))
+	assert.False(t, eso.IsLibraryName("MyLib"))
+	assert.False(t, eso.IsLibraryName("Li"))
+}
@@ -0,0 +1,118 @@
+package eso
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+)
+
+// RemovalImpact describes what would change if some AddOns were removed from the AddOns directory.
+type RemovalImpact struct {
+	Folders   []string         // Folders which would be removed, relative to the AddOns directory
+	Removed   []string         // Keys of the AddOns which would no longer be installed at all
+	Fallbacks []AddOn          // AddOns which would still be installed, but the game would load a different copy of
+	Broken    []LoadFailure    // AddOns which load now, but wouldn't once removed
+	Degraded  []LostDependency // AddOns which would still load, but lose an optional dependency
+	SavedVars []string         // SavedVariables files which would no longer belong to any installed AddOn
+}
+
+// LostDependency is an optional dependency an AddOn would lose.
+type LostDependency struct {
+	Key        string
+	Dependency string
+}
+
+// RemovalImpact works out what would stop loading if the given AddOns were removed. Every copy of each AddOn which
+// isn't embedded within another AddOn is removed (along with anything embedded within it), just as deleting its folder would.
+func (A AddOns) RemovalImpact(AppFs afero.Fs, names ...string) (RemovalImpact, error) {
+	var impact RemovalImpact
+	var remaining []AddOn
+
+	for _, name := range names {
+		addon, exists := A.Find(name)
+		if !exists {
+			return impact, fmt.Errorf("AddOn %q is not installed", name)
+		}
+
+		var folders []string
+		for _, instance := range addon.Instances() {
+			if instance.Parent() == "" {
+				folders = append(folders, instance.meta.dir)
+			}
+		}
+
+		if len(folders) == 0 {
+			return impact, fmt.Errorf("AddOn %q is only installed within %s, remove that instead", name, addon.Parent())
+		}
+
+		impact.Folders = append(impact.Folders, folders...)
+	}
+
+	sort.Strings(impact.Folders)
+
+	for _, instance := range A.Instances() {
+		if !impact.removes(instance.meta.dir) {
+			instance.meta.parent = ""
+			remaining = append(remaining, instance)
+		}
+	}
+
+	after := arbitrateAddOns(remaining)
+	beforePlan := NewDependencyGraph(A).LoadOrder()
+	afterPlan := NewDependencyGraph(after).LoadOrder()
+
+	for _, key := range A.Keys() {
+		addon, installed := after[key]
+
+		switch {
+		case !installed:
+			impact.Removed = append(impact.Removed, key)
+			continue
+		case addon.meta.dir != A[key].meta.dir:
+			impact.Fallbacks = append(impact.Fallbacks, addon)
+		}
+
+		if _, failedBefore := beforePlan.Failure(key); failedBefore {
+			continue
+		}
+
+		if failure, failed := afterPlan.Failure(key); failed {
+			impact.Broken = append(impact.Broken, failure)
+			continue
+		}
+
+		for _, dependency := range A[key].OptionalDependencies() {
+			_, loadedBefore := A.Find(dependency.Name)
+			_, failedBefore := beforePlan.Failure(ToKey(dependency.Name))
+			_, loadedAfter := after.Find(dependency.Name)
+			_, failedAfter := afterPlan.Failure(ToKey(dependency.Name))
+
+			if loadedBefore && !failedBefore && (!loadedAfter || failedAfter) {
+				impact.Degraded = append(impact.Degraded, LostDependency{Key: key, Dependency: ToKey(dependency.Name)})
+			}
+		}
+	}
+
+	for _, key := range impact.Removed {
+		savedVars := key + ".lua"
+
+		if exists, _ := afero.Exists(AppFs, filepath.Join(SavedVariablesPath(), savedVars)); exists {
+			impact.SavedVars = append(impact.SavedVars, savedVars)
+		}
+	}
+
+	return impact, nil
+}
+
+// Returns true if the directory is one of the folders being removed, or is within one of them.
+func (R RemovalImpact) removes(dir string) bool {
+	for _, folder := range R.Folders {
+		if dir == folder || isWithinDir(dir, folder) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package eso_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func removalAddOns(t *testing.T) (afero.Fs, eso.AddOns) {
+	t.Helper()
+
+	fs := addOnsFs(t, map[string]string{
+		"AddOns/MyAddon/MyAddon.txt":            "## Title: My Addon\n## DependsOn: LibA>=5\n## OptionalDependsOn: LibOpt\n",
+		"AddOns/LibA/LibA.txt":                  "## Title: LibA\n## AddOnVersion: 5\n",
+		"AddOns/Host/Host.txt":                  "## Title: Host\n",
+		"AddOns/Host/LibA/LibA.txt":             "## Title: LibA\n## AddOnVersion: 10\n",
+		"AddOns/Host/LibBundled/LibBundled.txt": "## Title: LibBundled\n",
+		"AddOns/LibOpt/LibOpt.txt":              "## Title: LibOpt\n",
+		"AddOns/LibB/LibB.txt":                  "## Title: LibB\n",
+		"AddOns/Middle/Middle.txt":              "## Title: Middle\n## DependsOn: LibB\n",
+		"AddOns/Top/Top.txt":                    "## Title: Top\n## DependsOn: Middle\n",
+		"SavedVariables/LibB.lua":               "LibB_SV = {}\n",
+	})
+
+	addons, errs := eso.GetAddOns(fs)
+	require.Empty(t, errs)
+
+	return fs, addons
+}
+
+func TestAddOns_RemovalImpact_Broken(t *testing.T) {
+	fs, addons := removalAddOns(t)
+
+	impact, err := addons.RemovalImpact(fs, "LibB")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.FromSlash("/LibB")}, impact.Folders)
+	assert.Equal(t, []string{"LibB"}, impact.Removed)
+	assert.Empty(t, impact.Fallbacks)
+	assert.Equal(t, []string{"LibB.lua"}, impact.SavedVars)
+
+	require.Len(t, impact.Broken, 2)
+	assert.Equal(t, "Middle", impact.Broken[0].Key)
+	assert.Equal(t, eso.LoadMissingDependency, impact.Broken[0].Reason)
+	assert.Equal(t, "Top", impact.Broken[1].Key)
+	assert.Equal(t, eso.LoadFailedDependency, impact.Broken[1].Reason)
+	assert.Equal(t, "Middle", impact.Broken[1].Cause)
+}
+
+func TestAddOns_RemovalImpact_Fallback(t *testing.T) {
+	fs, addons := removalAddOns(t)
+
+	impact, err := addons.RemovalImpact(fs, "Host")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Host", "LibBundled"}, impact.Removed)
+	require.Len(t, impact.Fallbacks, 1)
+
+	fallback := impact.Fallbacks[0]
+	assert.Equal(t, "LibA", fallback.Key())
+	assert.Equal(t, filepath.FromSlash("/LibA"), fallback.Dir())
+	assert.Equal(t, "5", fallback.AddOnVersion)
+	assert.Empty(t, impact.Broken)
+	assert.Empty(t, impact.SavedVars)
+}
+
+func TestAddOns_RemovalImpact_Degraded(t *testing.T) {
+	fs, addons := removalAddOns(t)
+
+	impact, err := addons.RemovalImpact(fs, "LibOpt", "Top")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"LibOpt", "Top"}, impact.Removed)
+	assert.Empty(t, impact.Broken)
+	assert.Equal(t, []eso.LostDependency{{Key: "MyAddon", Dependency: "LibOpt"}}, impact.Degraded)
+}
+
+func TestAddOns_RemovalImpact_Errors(t *testing.T) {
+	fs, addons := removalAddOns(t)
+
+	_, err := addons.RemovalImpact(fs, "Unknown")
+	assert.ErrorContains(t, err, "not installed")
+
+	_, err = addons.RemovalImpact(fs, "LibBundled")
+	assert.ErrorContains(t, err, "only installed within Host")
+}
@@ -0,0 +1,298 @@
+package eso
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// characterIdPattern matches the ids the game keys character data with
+var characterIdPattern = regexp.MustCompile(`^[0-9]{8,}$`)
+
+// AnonymizedName is a name replaced by an Anonymizer, and the pseudonym it was replaced with.
+type AnonymizedName struct {
+	Kind      string // "account", "character", "character id", or the key of the field the value was found in
+	Original  string
+	Pseudonym string
+}
+
+// Anonymizer replaces account names, character names and ids, and the values of chosen fields within SavedVariables
+// with pseudonyms, such as for sharing the files with AddOn authors. Each name is given the same pseudonym within
+// every file, so that the files still make sense together.
+//
+// Names are learned from every document first (see Learn), and then replaced wherever they appear (see Anonymize):
+// as whole keys, as whole values, or as whole words within other text, such as "@Account" within a guild roster note.
+type Anonymizer struct {
+	Fields []string // The keys of fields (e.g. "guildName") whose string values are replaced, matched case-insensitively
+
+	names      []AnonymizedName
+	pseudonyms map[string]string // Pseudonyms of accounts, characters, and ids, by original
+	values     map[string]string // Pseudonyms of field values, by lower-case field key and original
+	counts     map[string]int    // The number of pseudonyms given out of each kind, and for each field (by lower-case key and a NUL)
+	pattern    *regexp.Regexp    // Matches any learned name, longest first
+}
+
+// NewAnonymizer returns an Anonymizer which also replaces the values of the given fields.
+func NewAnonymizer(fields ...string) *Anonymizer {
+	return &Anonymizer{
+		Fields:     fields,
+		pseudonyms: make(map[string]string),
+		values:     make(map[string]string),
+		counts:     make(map[string]int),
+	}
+}
+
+// Learn gives a pseudonym to each account, character name, and character id within the document.
+func (A *Anonymizer) Learn(document *LuaDocument) {
+	for _, account := range document.accounts() {
+		A.learn("account", account.field.KeyString())
+	}
+
+	for _, character := range document.Characters() {
+		if characterIdPattern.MatchString(character.Key) {
+			A.learn("character id", character.Key)
+		}
+
+		// Names may be stored with a grammatical gender suffix, such as "Name^Mx", which is left as it is
+		A.learn("character", strings.SplitN(character.Name, "^", 2)[0])
+	}
+}
+
+// Anonymize returns the document's source with every learned name, and the values of Fields, replaced by their
+// pseudonyms. Everything else is left exactly as it was.
+func (A *Anonymizer) Anonymize(document *LuaDocument) ([]byte, error) {
+	var edits []LuaEdit
+
+	for _, field := range document.Table().Fields {
+		edits = append(edits, A.anonymizeTable(field.Value)...)
+	}
+
+	return document.Apply(edits...)
+}
+
+// Names returns each name which was replaced, along with its pseudonym, in the order they were learned.
+func (A *Anonymizer) Names() []AnonymizedName {
+	return A.names
+}
+
+// Returns the edits anonymizing the keys and values within a table
+func (A *Anonymizer) anonymizeTable(table *LuaValue) []LuaEdit {
+	var edits []LuaEdit
+
+	if table.Kind != LuaTable {
+		return edits
+	}
+
+	for _, field := range table.Fields {
+		if field.Key != nil {
+			if edit, ok := A.anonymizeKey(field); ok {
+				edits = append(edits, edit)
+			}
+		}
+
+		switch field.Value.Kind {
+		case LuaTable:
+			edits = append(edits, A.anonymizeTable(field.Value)...)
+		case LuaString:
+			if replaced := A.anonymizeString(field); replaced != field.Value.String {
+				edits = append(edits, LuaEdit{Start: field.Value.Start.Offset, End: field.Value.End, Text: QuoteLuaString(replaced)})
+			}
+		case LuaNumber:
+			if pseudonym, ok := A.pseudonyms[field.Value.Raw]; ok {
+				edits = append(edits, LuaEdit{Start: field.Value.Start.Offset, End: field.Value.End, Text: pseudonym})
+			}
+		}
+	}
+
+	return edits
+}
+
+// Returns the edit anonymizing a field's key, if it contains any learned names
+func (A *Anonymizer) anonymizeKey(field *LuaField) (LuaEdit, bool) {
+	var key = field.Key
+	var edit = LuaEdit{Start: key.Start.Offset, End: key.End}
+
+	switch key.Kind {
+	case LuaString:
+		replaced, ok := A.replaceWhole(key.String)
+		if !ok {
+			return edit, false
+		}
+
+		if field.Identifier && isLuaName(replaced) {
+			edit.Text = replaced
+		} else if field.Identifier {
+			edit.Text = "[" + QuoteLuaString(replaced) + "]"
+		} else {
+			edit.Text = QuoteLuaString(replaced)
+		}
+	case LuaNumber:
+		pseudonym, ok := A.pseudonyms[key.Raw]
+		if !ok {
+			return edit, false
+		}
+
+		edit.Text = pseudonym
+	default:
+		return edit, false
+	}
+
+	return edit, true
+}
+
+// Returns a string value with its pseudonym, if it's within one of Fields or is a learned name, or with any learned
+// names within it replaced
+func (A *Anonymizer) anonymizeString(field *LuaField) string {
+	if field.Key != nil && field.Value.String != "" {
+		for _, name := range A.Fields {
+			if strings.EqualFold(name, field.KeyString()) {
+				return A.fieldPseudonym(field.KeyString(), field.Value.String)
+			}
+		}
+	}
+
+	if replaced, ok := A.replaceWhole(field.Value.String); ok {
+		return replaced
+	}
+
+	return A.replaceWords(field.Value.String)
+}
+
+// Returns the pseudonym of s if it's a learned name, keeping any grammatical gender suffix (such as "Name^Mx")
+func (A *Anonymizer) replaceWhole(s string) (string, bool) {
+	if pseudonym, ok := A.pseudonyms[s]; ok {
+		return pseudonym, true
+	}
+
+	if name, suffix, found := strings.Cut(s, "^"); found {
+		if pseudonym, ok := A.pseudonyms[name]; ok {
+			return pseudonym + "^" + suffix, true
+		}
+	}
+
+	return s, false
+}
+
+// Returns s with every learned name which appears as a whole word replaced by its pseudonym, so that a name such as
+// "Sam" is replaced within "Sam's house" but not within "Samples"
+func (A *Anonymizer) replaceWords(s string) string {
+	if len(A.pseudonyms) == 0 {
+		return s
+	}
+
+	if A.pattern == nil {
+		var originals []string
+
+		for original := range A.pseudonyms {
+			originals = append(originals, original)
+		}
+
+		// Longer names are matched first, so that a name containing another is replaced whole
+		sort.Slice(originals, func(i, j int) bool {
+			if len(originals[i]) != len(originals[j]) {
+				return len(originals[i]) > len(originals[j])
+			}
+
+			return originals[i] < originals[j]
+		})
+
+		for i, original := range originals {
+			originals[i] = regexp.QuoteMeta(original)
+		}
+
+		A.pattern = regexp.MustCompile(strings.Join(originals, "|"))
+	}
+
+	var output strings.Builder
+	var pos = 0
+
+	for _, match := range A.pattern.FindAllStringIndex(s, -1) {
+		start, end := match[0], match[1]
+
+		if !isWordBoundary(s, start) || !isWordBoundary(s, end) {
+			continue
+		}
+
+		output.WriteString(s[pos:start])
+		output.WriteString(A.pseudonyms[s[start:end]])
+		pos = end
+	}
+
+	output.WriteString(s[pos:])
+
+	return output.String()
+}
+
+// Returns true if the characters on either side of offset aren't both part of a word
+func isWordBoundary(s string, offset int) bool {
+	before, _ := utf8.DecodeLastRuneInString(s[:offset])
+	after, _ := utf8.DecodeRuneInString(s[offset:])
+
+	return !isWordRune(before) || !isWordRune(after)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Gives a name the next pseudonym of its kind, unless it already has one
+func (A *Anonymizer) learn(kind string, original string) {
+	if _, ok := A.pseudonyms[original]; ok || original == "" {
+		return
+	}
+
+	A.counts[kind]++
+
+	var pseudonym string
+
+	switch kind {
+	case "account":
+		pseudonym = fmt.Sprintf("@Account%d", A.counts[kind])
+	case "character id":
+		pseudonym = strconv.Itoa(1000000000000000 + A.counts[kind])
+	default:
+		pseudonym = fmt.Sprintf("Character%d", A.counts[kind])
+	}
+
+	A.pseudonyms[original] = pseudonym
+	A.names = append(A.names, AnonymizedName{Kind: kind, Original: original, Pseudonym: pseudonym})
+	A.pattern = nil
+}
+
+// Returns the pseudonym of a value within one of Fields, such as "guildName1"
+func (A *Anonymizer) fieldPseudonym(key string, original string) string {
+	var counter = strings.ToLower(key) + "\x00"
+	var id = counter + original
+
+	if pseudonym, ok := A.values[id]; ok {
+		return pseudonym
+	}
+
+	A.counts[counter]++
+
+	pseudonym := fmt.Sprintf("%s%d", key, A.counts[counter])
+
+	A.values[id] = pseudonym
+	A.names = append(A.names, AnonymizedName{Kind: key, Original: original, Pseudonym: pseudonym})
+
+	return pseudonym
+}
+
+// Returns whether s may be written as a bare name, rather than a quoted key
+func isLuaName(s string) bool {
+	if s == "" || !isNameStart(s[0]) {
+		return false
+	}
+
+	for i := 1; i < len(s); i++ {
+		if !isNameChar(s[i]) {
+			return false
+		}
+	}
+
+	return true
+}
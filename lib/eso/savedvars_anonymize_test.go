@@ -0,0 +1,151 @@
+package eso_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymizer(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+	other := parseLua(t, `Other_SV =
+{
+    ["Default"] =
+    {
+        ["@dyoung522"] =
+        {
+            ["8798292047654321"] =
+            {
+                ["guildName"] = "Tamriel Traders",
+                ["note"] = "Invited by @dyoung522 on Dovahkiin",
+                ["charId"] = 8798292047123456,
+            },
+        },
+    },
+    ["guilds"] =
+    {
+        [1] =
+        {
+            ["GuildName"] = "Tamriel Traders",
+        },
+        [2] =
+        {
+            ["guildName"] = "Other Guild",
+        },
+    },
+    Dovahkiin = true,
+}
+`)
+
+	anonymizer := eso.NewAnonymizer("guildName")
+	anonymizer.Learn(document)
+	anonymizer.Learn(other)
+
+	assert.Equal(t, []eso.AnonymizedName{
+		{Kind: "account", Original: "@dyoung522", Pseudonym: "@Account1"},
+		{Kind: "character id", Original: "8798292047123456", Pseudonym: "1000000000000001"},
+		{Kind: "character", Original: "Dovahkiin", Pseudonym: "Character1"},
+		{Kind: "character id", Original: "8798292047654321", Pseudonym: "1000000000000002"},
+		{Kind: "character", Original: "Ærøn the Swift", Pseudonym: "Character2"},
+	}, anonymizer.Names())
+
+	output, err := anonymizer.Anonymize(document)
+	require.NoError(t, err)
+
+	expected := string(document.Source)
+	for _, name := range anonymizer.Names() {
+		expected = strings.ReplaceAll(expected, name.Original, name.Pseudonym)
+	}
+	assert.Equal(t, expected, string(output))
+
+	output, err = anonymizer.Anonymize(other)
+	require.NoError(t, err)
+	assert.Equal(t, `Other_SV =
+{
+    ["Default"] =
+    {
+        ["@Account1"] =
+        {
+            ["1000000000000002"] =
+            {
+                ["guildName"] = "guildName1",
+                ["note"] = "Invited by @Account1 on Character1",
+                ["charId"] = 1000000000000001,
+            },
+        },
+    },
+    ["guilds"] =
+    {
+        [1] =
+        {
+            ["GuildName"] = "guildName1",
+        },
+        [2] =
+        {
+            ["guildName"] = "guildName2",
+        },
+    },
+    Character1 = true,
+}
+`, string(output))
+
+	assert.Len(t, anonymizer.Names(), 7)
+}
+
+func TestAnonymizer_Keys(t *testing.T) {
+	document := parseLua(t, `SV = { ["@Some Account"] = { ["Old Name^Fx"] = { x = 1 } }, Old_Name = 1, [12345678] = "12345678" }`)
+
+	anonymizer := eso.NewAnonymizer()
+	anonymizer.Learn(document)
+	anonymizer.Learn(parseLua(t, `Ids = { ["@Some Account"] = { ["12345678"] = { ["$LastCharacterName"] = "Old_Name" } } }`))
+
+	output, err := anonymizer.Anonymize(document)
+	require.NoError(t, err)
+	assert.Equal(t, `SV = { ["@Account1"] = { ["Character1^Fx"] = { x = 1 } }, Character2 = 1, [1000000000000001] = "1000000000000001" }`, string(output))
+}
+
+func TestAnonymizer_WholeNames(t *testing.T) {
+	document := parseLua(t, `SV =
+{
+    ["@Acct"] =
+    {
+        ["12345678"] =
+        {
+            ["$LastCharacterName"] = "Sam",
+            ["Samples"] =
+            {
+                ["Sam"] = "Sample text",
+                ["note"] = "Sam's samples, for Sam and @Acct (not @Acct2)",
+                Samuel = "Samuel",
+            },
+        },
+    },
+}
+`)
+
+	anonymizer := eso.NewAnonymizer()
+	anonymizer.Learn(document)
+
+	output, err := anonymizer.Anonymize(document)
+	require.NoError(t, err)
+	assert.Equal(t, `SV =
+{
+    ["@Account1"] =
+    {
+        ["1000000000000001"] =
+        {
+            ["$LastCharacterName"] = "Character1",
+            ["Samples"] =
+            {
+                ["Character1"] = "Sample text",
+                ["note"] = "Character1's samples, for Character1 and @Account1 (not @Acct2)",
+                Samuel = "Samuel",
+            },
+        },
+    },
+}
+`, string(output))
+}
@@ -0,0 +1,105 @@
+package eso
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// FindSavedVarsBackups returns the SavedVariables backups (saved_variables_*.zip) within dir, newest first.
+func FindSavedVarsBackups(AppFs afero.Fs, dir string) ([]string, error) {
+	backups, err := afero.Glob(AppFs, filepath.Join(dir, "saved_variables_*.zip"))
+	if err != nil {
+		return nil, fmt.Errorf("error searching for backups in %q: %w", dir, err)
+	}
+
+	// Backups are named with their timestamp, so sorting by name sorts them by age
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	return backups, nil
+}
+
+// FindSavedVarsBackup returns the newest SavedVariables backup within dir which contains the named file
+// (e.g. "MyAddon.lua"), as backups may only contain some files.
+func FindSavedVarsBackup(AppFs afero.Fs, dir string, name string) (string, error) {
+	backups, err := FindSavedVarsBackups(AppFs, dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, backup := range backups {
+		if _, ok, err := readBackupEntry(AppFs, backup, name); ok && err == nil {
+			return backup, nil
+		}
+	}
+
+	if len(backups) == 0 {
+		return "", fmt.Errorf("no SavedVariables backups (saved_variables_*.zip) found in %q", dir)
+	}
+
+	return "", fmt.Errorf("none of the SavedVariables backups in %q contain %q", dir, name)
+}
+
+// ReadSavedVarsBackup reads and parses the named SavedVariables file (e.g. "MyAddon.lua") from a backup archive.
+func ReadSavedVarsBackup(AppFs afero.Fs, archive string, name string) (*LuaDocument, error) {
+	data, ok, err := readBackupEntry(AppFs, archive, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%q does not contain %q", archive, name)
+	}
+
+	document, err := ParseLua(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %q from %q: %w", name, archive, err)
+	}
+
+	return document, nil
+}
+
+// Returns the contents of the named file within a ZIP archive, or false if it isn't there
+func readBackupEntry(AppFs afero.Fs, archive string, name string) ([]byte, bool, error) {
+	file, err := AppFs.Open(archive)
+	if err != nil {
+		return nil, false, fmt.Errorf("error opening %q: %w", archive, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading %q: %w", archive, err)
+	}
+
+	reader, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading %q: %w", archive, err)
+	}
+
+	for _, entry := range reader.File {
+		if !strings.EqualFold(filepath.Base(entry.Name), name) {
+			continue
+		}
+
+		contents, err := entry.Open()
+		if err != nil {
+			return nil, true, fmt.Errorf("error reading %q from %q: %w", entry.Name, archive, err)
+		}
+		defer contents.Close()
+
+		data, err := io.ReadAll(contents)
+		if err != nil {
+			return nil, true, fmt.Errorf("error reading %q from %q: %w", entry.Name, archive, err)
+		}
+
+		return data, true, nil
+	}
+
+	return nil, false, nil
+}
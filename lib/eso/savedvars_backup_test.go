@@ -0,0 +1,97 @@
+package eso_test
+
+import (
+	"archive/zip"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeZip(t *testing.T, fs afero.Fs, path string, files map[string]string) {
+	t.Helper()
+
+	file, err := fs.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for name, contents := range files {
+		entry, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = entry.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+}
+
+func TestFindSavedVarsBackups(t *testing.T) {
+	var fs = afero.NewMemMapFs()
+
+	writeZip(t, fs, "/backups/saved_variables_20240101120000.zip", nil)
+	writeZip(t, fs, "/backups/saved_variables_20241001120000.zip", nil)
+	writeZip(t, fs, "/backups/saved_variables_20240601120000.zip", nil)
+	writeZip(t, fs, "/backups/addons_20241101120000.zip", nil)
+
+	backups, err := eso.FindSavedVarsBackups(fs, "/backups")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"/backups/saved_variables_20241001120000.zip",
+		"/backups/saved_variables_20240601120000.zip",
+		"/backups/saved_variables_20240101120000.zip",
+	}, backups)
+
+	backups, err = eso.FindSavedVarsBackups(fs, "/elsewhere")
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+}
+
+func TestFindSavedVarsBackup(t *testing.T) {
+	var fs = afero.NewMemMapFs()
+
+	writeZip(t, fs, "/backups/saved_variables_20240101120000.zip", map[string]string{"MyAddon.lua": "", "Other.lua": ""})
+	writeZip(t, fs, "/backups/saved_variables_20241001120000.zip", map[string]string{"MyAddon.lua": ""})
+	writeZip(t, fs, "/backups/saved_variables_20241001120000_02.zip", map[string]string{"Third.lua": ""})
+
+	backup, err := eso.FindSavedVarsBackup(fs, "/backups", "myaddon.lua")
+	require.NoError(t, err)
+	assert.Equal(t, "/backups/saved_variables_20241001120000.zip", backup)
+
+	backup, err = eso.FindSavedVarsBackup(fs, "/backups", "Other.lua")
+	require.NoError(t, err)
+	assert.Equal(t, "/backups/saved_variables_20240101120000.zip", backup)
+
+	backup, err = eso.FindSavedVarsBackup(fs, "/backups", "Third.lua")
+	require.NoError(t, err)
+	assert.Equal(t, "/backups/saved_variables_20241001120000_02.zip", backup)
+
+	_, err = eso.FindSavedVarsBackup(fs, "/backups", "Missing.lua")
+	assert.EqualError(t, err, `none of the SavedVariables backups in "/backups" contain "Missing.lua"`)
+
+	_, err = eso.FindSavedVarsBackup(fs, "/elsewhere", "MyAddon.lua")
+	assert.ErrorContains(t, err, "no SavedVariables backups")
+}
+
+func TestReadSavedVarsBackup(t *testing.T) {
+	var fs = afero.NewMemMapFs()
+
+	writeZip(t, fs, "/backups/saved_variables_20241001120000.zip", map[string]string{
+		"MyAddon.lua": `MyAddon_SV = { ["version"] = 2 }`,
+		"Broken.lua":  `Broken_SV = {`,
+	})
+
+	document, err := eso.ReadSavedVarsBackup(fs, "/backups/saved_variables_20241001120000.zip", "myaddon.lua")
+	require.NoError(t, err)
+	assert.Equal(t, float64(2), document.Get("MyAddon_SV").Get("version").Number)
+
+	_, err = eso.ReadSavedVarsBackup(fs, "/backups/saved_variables_20241001120000.zip", "Other.lua")
+	assert.EqualError(t, err, `"/backups/saved_variables_20241001120000.zip" does not contain "Other.lua"`)
+
+	_, err = eso.ReadSavedVarsBackup(fs, "/backups/saved_variables_20241001120000.zip", "Broken.lua")
+	assert.ErrorContains(t, err, `error parsing "Broken.lua"`)
+
+	_, err = eso.ReadSavedVarsBackup(fs, "/backups/missing.zip", "MyAddon.lua")
+	assert.Error(t, err)
+}
@@ -0,0 +1,133 @@
+package eso
+
+import (
+	"strings"
+)
+
+// accountSearchDepth is how many tables deep account keys (e.g. "@dyoung522") are searched for within each variable.
+// ZO_SavedVars stores them at Variable[namespace][account], but some AddOns add a level or two of their own.
+const accountSearchDepth = 3
+
+const (
+	AccountWide       = "$AccountWide"       // The key of the data shared by every character on an account
+	LastCharacterName = "$LastCharacterName" // The key ZO_SavedVars records each character's name under
+)
+
+// SavedVarsCharacter is the data stored for a single character within a SavedVariables file.
+// ZO_SavedVars keys character data by character id (or by name, in older files) beneath the account, alongside the
+// "$AccountWide" data shared by every character.
+type SavedVarsCharacter struct {
+	Account string   // The account name, e.g. "@dyoung522"
+	Key     string   // The character id, or name if keyed by name
+	Name    string   // The character name ($LastCharacterName), or Key if unknown
+	Path    []string // The keys leading to the character's data, starting with the variable name
+	Size    int      // The number of bytes the character's data takes up
+}
+
+// ID returns the account and key which uniquely identify the character across SavedVariables files.
+func (C SavedVarsCharacter) ID() string {
+	return C.Account + "/" + C.Key
+}
+
+// String returns the character's name and id, e.g. "Dovahkiin (8798292047123456)".
+func (C SavedVarsCharacter) String() string {
+	if C.Name == C.Key {
+		return C.Name
+	}
+
+	return C.Name + " (" + C.Key + ")"
+}
+
+// Matches returns true if name is the character's key or name (case-insensitive).
+func (C SavedVarsCharacter) Matches(name string) bool {
+	return C.Key == name || strings.EqualFold(C.Name, name) || strings.EqualFold(C.Key, name)
+}
+
+// Characters returns every character's data stored in the document, in source order.
+func (D LuaDocument) Characters() []SavedVarsCharacter {
+	var characters []SavedVarsCharacter
+
+	for _, account := range D.accounts() {
+		for _, character := range account.field.Value.Fields {
+			var characterKey = character.KeyString()
+
+			// Skip $AccountWide and any other special keys
+			if strings.HasPrefix(characterKey, "$") || character.Value.Kind != LuaTable {
+				continue
+			}
+
+			var name = characterKey
+			if lastName := character.Value.Get(LastCharacterName); lastName != nil && lastName.Kind == LuaString {
+				name = lastName.String
+			}
+
+			edit := D.DeleteField(character)
+
+			characters = append(characters, SavedVarsCharacter{
+				Account: account.field.KeyString(),
+				Key:     characterKey,
+				Name:    name,
+				Path:    append(account.path[:len(account.path):len(account.path)], characterKey),
+				Size:    edit.End - edit.Start,
+			})
+		}
+	}
+
+	return characters
+}
+
+// An account's table (e.g. "@dyoung522") within a SavedVariables file, and the keys leading to it
+type savedVarsAccount struct {
+	path  []string
+	field *LuaField
+}
+
+// Returns every account table within the document, in source order
+func (D LuaDocument) accounts() []savedVarsAccount {
+	var accounts []savedVarsAccount
+
+	for _, field := range D.Table().Fields {
+		accounts = append(accounts, findAccounts(field.Value, []string{field.KeyString()}, accountSearchDepth)...)
+	}
+
+	return accounts
+}
+
+func findAccounts(table *LuaValue, path []string, depth int) []savedVarsAccount {
+	var accounts []savedVarsAccount
+
+	if table.Kind != LuaTable || depth == 0 {
+		return accounts
+	}
+
+	for _, field := range table.Fields {
+		var key = field.KeyString()
+		var fieldPath = append(path[:len(path):len(path)], key)
+
+		if strings.HasPrefix(key, "@") && field.Value.Kind == LuaTable {
+			accounts = append(accounts, savedVarsAccount{path: fieldPath, field: field})
+			continue
+		}
+
+		accounts = append(accounts, findAccounts(field.Value, fieldPath, depth-1)...)
+	}
+
+	return accounts
+}
+
+// RemoveCharacters returns the document's source with the data of the given characters removed. Everything else
+// is left exactly as it was.
+func (D LuaDocument) RemoveCharacters(characters ...SavedVarsCharacter) ([]byte, error) {
+	var edits []LuaEdit
+
+	for _, character := range characters {
+		field, err := D.FieldAt(character.Path...)
+		if err != nil {
+			return nil, err
+		}
+
+		edits = append(edits, D.DeleteField(field))
+	}
+
+	return D.Apply(edits...)
+}
@@ -0,0 +1,86 @@
+package eso_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLuaDocument_Characters(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	characters := document.Characters()
+	require.Len(t, characters, 2)
+
+	assert.Equal(t, "@dyoung522", characters[0].Account)
+	assert.Equal(t, "8798292047123456", characters[0].Key)
+	assert.Equal(t, "Dovahkiin", characters[0].Name)
+	assert.Equal(t, []string{"MyAddon_SavedVariables", "Default", "@dyoung522", "8798292047123456"}, characters[0].Path)
+	assert.Equal(t, "@dyoung522/8798292047123456", characters[0].ID())
+	assert.Equal(t, "Dovahkiin (8798292047123456)", characters[0].String())
+	assert.Greater(t, characters[0].Size, 0)
+
+	assert.Equal(t, "Ærøn the Swift", characters[1].Name)
+	assert.True(t, characters[1].Matches("ærøn THE swift"))
+	assert.True(t, characters[1].Matches("8798292047654321"))
+	assert.False(t, characters[1].Matches("Dovahkiin"))
+}
+
+func TestLuaDocument_CharactersByName(t *testing.T) {
+	document := parseLua(t, `
+Legacy_SV = {
+	["Default"] = {
+		["@someone"] = {
+			["$AccountWide"] = { ["version"] = 1 },
+			["Old Name^Mx"] = { ["version"] = 1 },
+		},
+	},
+}
+Profiles_SV = {
+	["Default"] = { ["Profiles"] = { ["@someone"] = { ["Other Name"] = {} } } },
+	["TooDeep"] = { ["a"] = { ["b"] = { ["@someone"] = { ["Hidden"] = {} } } } },
+}
+Plain_SV = { ["version"] = 1 }
+`)
+
+	var names []string
+	for _, character := range document.Characters() {
+		names = append(names, character.String())
+	}
+
+	assert.Equal(t, []string{"Old Name^Mx", "Other Name"}, names)
+}
+
+func TestLuaDocument_RemoveCharacters(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	characters := document.Characters()
+
+	output, err := document.RemoveCharacters(characters[1])
+	require.NoError(t, err)
+	assert.Equal(t, len(document.Source)-characters[1].Size, len(output))
+	assert.NotContains(t, string(output), "8798292047654321")
+
+	updated, err := eso.ParseLua(output)
+	require.NoError(t, err)
+
+	changes := eso.DiffLuaDocuments(document, updated)
+	require.Len(t, changes, 1)
+	assert.Equal(t, eso.LuaRemoved, changes[0].Type)
+	assert.Equal(t, characters[1].Path, changes[0].Path)
+
+	// Everything else is left exactly as it was
+	source := string(document.Source)
+	assert.True(t, strings.HasPrefix(string(output), source[:strings.Index(source, `            ["8798292047654321"]`)]))
+
+	output, err = document.RemoveCharacters(characters...)
+	require.NoError(t, err)
+
+	updated, err = eso.ParseLua(output)
+	require.NoError(t, err)
+	assert.Empty(t, updated.Characters())
+	assert.Equal(t, []string{"$AccountWide"}, updated.Get("MyAddon_SavedVariables").Get("Default").Get("@dyoung522").Keys())
+}
@@ -0,0 +1,70 @@
+package eso
+
+import (
+	"sort"
+	"strings"
+)
+
+// SavedVarsClaim is which installed AddOns claim a SavedVariables file, and each of the variables within it.
+type SavedVarsClaim struct {
+	File      string
+	Owner     string              // The key of the installed AddOn the file is named after, if any
+	Variables map[string][]string // The keys of the installed AddOns declaring each variable within the file
+	Unclaimed []string            // The variables within the file which no installed AddOn declares, in file order
+}
+
+// ClaimSavedVars returns which installed AddOns claim a SavedVariables file, by its name and by the variables declared
+// within it (## SavedVariables: Name). An AddOn's variables aren't always named after it, and one file may hold the
+// variables of several AddOns, so each variable is matched against every AddOn's declarations.
+func (A AddOns) ClaimSavedVars(file string, variables []string) SavedVarsClaim {
+	var claim = SavedVarsClaim{File: file, Variables: make(map[string][]string)}
+
+	if addon, ok := A.Find(strings.TrimSuffix(file, ".lua")); ok {
+		claim.Owner = addon.Key()
+	}
+
+	for _, variable := range variables {
+		if _, ok := claim.Variables[variable]; ok {
+			continue
+		}
+
+		claim.Variables[variable] = A.SavedVarsDeclaredBy(variable)
+
+		if len(claim.Variables[variable]) == 0 {
+			claim.Unclaimed = append(claim.Unclaimed, variable)
+		}
+	}
+
+	return claim
+}
+
+// Claimed returns whether any installed AddOn claims the file, by declaring one of its variables or by being named
+// after it. The game rewrites a file named after an installed AddOn whenever it saves, so such files are never
+// extraneous, even if none of their variables are still declared.
+func (C SavedVarsClaim) Claimed() bool {
+	return C.Owner != "" || len(C.Unclaimed) < len(C.Variables)
+}
+
+// Claimants returns the keys of every installed AddOn claiming the file, sorted.
+func (C SavedVarsClaim) Claimants() []string {
+	var seen = make(map[string]bool)
+	var keys []string
+
+	if C.Owner != "" {
+		seen[C.Owner] = true
+		keys = append(keys, C.Owner)
+	}
+
+	for _, declaredBy := range C.Variables {
+		for _, key := range declaredBy {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
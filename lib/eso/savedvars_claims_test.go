@@ -0,0 +1,39 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddOns_ClaimSavedVars(t *testing.T) {
+	addons := savedVarsAddOns(t)
+
+	tests := []struct {
+		name      string
+		file      string
+		variables []string
+		owner     string
+		unclaimed []string
+		claimants []string
+		claimed   bool
+	}{
+		{"named after its AddOn", "MyAddon.lua", []string{"MyAddon_SV", "MyAddon_Globals"}, "MyAddon", nil, []string{"MyAddon", "Shared"}, true},
+		{"variables named differently", "OldName.lua", []string{"OldName_SV"}, "", nil, []string{"Renamed"}, true},
+		{"several AddOns", "Combined.lua", []string{"OldName_SV", "MyAddon_SV", "Gone_SV"}, "", []string{"Gone_SV"}, []string{"MyAddon", "Renamed"}, true},
+		{"no longer declared", "MyAddon.lua", []string{"MyAddon_Old"}, "MyAddon", []string{"MyAddon_Old"}, []string{"MyAddon"}, true},
+		{"unreadable", "NoSaved.lua", nil, "NoSaved", nil, []string{"NoSaved"}, true},
+		{"extraneous", "Gone.lua", []string{"Gone_SV", "Gone_SV"}, "", []string{"Gone_SV"}, nil, false},
+		{"empty", "Gone.lua", nil, "", nil, nil, false},
+	}
+
+	for _, test := range tests {
+		claim := addons.ClaimSavedVars(test.file, test.variables)
+
+		assert.Equal(t, test.file, claim.File, test.name)
+		assert.Equal(t, test.owner, claim.Owner, test.name)
+		assert.Equal(t, test.unclaimed, claim.Unclaimed, test.name)
+		assert.Equal(t, test.claimants, claim.Claimants(), test.name)
+		assert.Equal(t, test.claimed, claim.Claimed(), test.name)
+	}
+}
@@ -0,0 +1,106 @@
+package eso
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SavedVarsCopy is a single table of settings copied by LuaDocument.CopySettings.
+type SavedVarsCopy struct {
+	From     []string // The keys leading to the copied data, starting with the variable name
+	To       []string // The keys leading to where it was copied
+	Replaced bool     // True if existing data was replaced, false if it was added
+}
+
+// AccountWideOwner returns the owner of an account's "$AccountWide" data, so it may be copied to or from like a
+// character's.
+func AccountWideOwner(account string) SavedVarsCharacter {
+	return SavedVarsCharacter{Account: account, Key: AccountWide, Name: AccountWide}
+}
+
+// CopySettings returns the document's source with the settings of `from` copied over the settings of each of `to`,
+// within every table holding settings for `from`. Settings which `to` doesn't have yet are added. Either may be
+// account-wide (see AccountWideOwner), converting between character-specific and account-wide settings.
+//
+// The copied character name ($LastCharacterName) is replaced with the name of the character copied to, and removed
+// when copying to account-wide settings. Everything outside the replaced tables is left exactly as it was.
+func (D LuaDocument) CopySettings(from SavedVarsCharacter, to ...SavedVarsCharacter) ([]byte, []SavedVarsCopy, error) {
+	var edits []LuaEdit
+	var copies []SavedVarsCopy
+
+	for _, target := range to {
+		if target.ID() == from.ID() {
+			return nil, nil, fmt.Errorf("can't copy the settings of %s to itself", from)
+		}
+	}
+
+	for _, account := range D.accounts() {
+		if account.field.KeyString() != from.Account {
+			continue
+		}
+
+		source := account.field.Value.Field(from.Key)
+		if source == nil || source.Value.Kind != LuaTable {
+			continue
+		}
+
+		var parent = account.path[:len(account.path)-1]
+		var level = len(account.path) // The level of the account's fields
+
+		for _, target := range to {
+			var targetAccount = account.field.Value
+
+			if target.Account != from.Account {
+				field, err := D.FieldAt(append(parent[:len(parent):len(parent)], target.Account)...)
+				if err != nil || field.Value.Kind != LuaTable {
+					continue // The target account has no settings for this variable
+				}
+
+				targetAccount = field.Value
+			}
+
+			value := source.Value.Clone()
+
+			if target.Key == AccountWide || target.Name == target.Key {
+				value.Delete(LastCharacterName)
+			} else {
+				value.Set(LastCharacterName, NewLuaString(target.Name))
+			}
+
+			var result = SavedVarsCopy{
+				From: append(account.path[:len(account.path):len(account.path)], from.Key),
+				To:   append(parent[:len(parent):len(parent)], target.Account, target.Key),
+			}
+
+			if existing := targetAccount.Field(target.Key); existing != nil {
+				result.Replaced = true
+				edits = append(edits, D.ReplaceField(existing, value, level))
+			} else {
+				edits = append(edits, D.InsertField(targetAccount, &LuaField{Key: NewLuaString(target.Key), Value: value}, level))
+			}
+
+			copies = append(copies, result)
+		}
+	}
+
+	output, err := D.Apply(edits...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return output, copies, nil
+}
+
+// ParseSavedVarsOwner splits an owner given as "@Account/Character" into its account and character, either of which
+// may be empty (e.g. "Character" or "@Account/$AccountWide").
+func ParseSavedVarsOwner(owner string) (account string, character string) {
+	if strings.HasPrefix(owner, "@") {
+		if account, character, found := strings.Cut(owner, "/"); found {
+			return account, character
+		}
+
+		return owner, ""
+	}
+
+	return "", owner
+}
@@ -0,0 +1,147 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleCharacters(t *testing.T, document *eso.LuaDocument) (eso.SavedVarsCharacter, eso.SavedVarsCharacter) {
+	t.Helper()
+
+	characters := document.Characters()
+	require.Len(t, characters, 2)
+
+	return characters[0], characters[1]
+}
+
+func TestLuaDocument_CopySettings(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+	dovahkiin, aeron := sampleCharacters(t, document)
+
+	output, copies, err := document.CopySettings(dovahkiin, aeron)
+	require.NoError(t, err)
+
+	require.Len(t, copies, 1)
+	assert.Equal(t, dovahkiin.Path, copies[0].From)
+	assert.Equal(t, aeron.Path, copies[0].To)
+	assert.True(t, copies[0].Replaced)
+
+	updated, err := eso.ParseLua(output)
+	require.NoError(t, err)
+
+	changes := eso.DiffLuaDocuments(document, updated)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "MyAddon_SavedVariables.Default.@dyoung522.8798292047654321.enabled", changes[0].PathString())
+
+	copied := updated.Get("MyAddon_SavedVariables").Get("Default").Get("@dyoung522").Get(aeron.Key)
+	assert.Equal(t, "Ærøn the Swift", copied.Get(eso.LastCharacterName).String)
+	assert.True(t, copied.Get("enabled").Bool)
+}
+
+func TestLuaDocument_CopySettingsToAccountWide(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+	dovahkiin, _ := sampleCharacters(t, document)
+
+	output, copies, err := document.CopySettings(dovahkiin, eso.AccountWideOwner("@dyoung522"))
+	require.NoError(t, err)
+	require.Len(t, copies, 1)
+	assert.True(t, copies[0].Replaced)
+
+	updated, err := eso.ParseLua(output)
+	require.NoError(t, err)
+
+	accountWide := updated.Get("MyAddon_SavedVariables").Get("Default").Get("@dyoung522").Get(eso.AccountWide)
+	assert.Equal(t, []string{"version", "enabled"}, accountWide.Keys())
+}
+
+func TestLuaDocument_CopySettingsFromAccountWide(t *testing.T) {
+	document := parseLua(t, `
+SV =
+{
+    ["Default"] = 
+    {
+        ["@Acct"] = 
+        {
+            ["$AccountWide"] = 
+            {
+                ["color"] = "red",
+            },
+        },
+        ["@Other"] = 
+        {
+        },
+    },
+}
+`)
+
+	target := eso.SavedVarsCharacter{Account: "@Acct", Key: "1234", Name: "New Alt"}
+	elsewhere := eso.SavedVarsCharacter{Account: "@Other", Key: "5678", Name: "Other Alt"}
+	missing := eso.SavedVarsCharacter{Account: "@Missing", Key: "9999", Name: "Missing"}
+
+	output, copies, err := document.CopySettings(eso.AccountWideOwner("@Acct"), target, elsewhere, missing)
+	require.NoError(t, err)
+
+	require.Len(t, copies, 2)
+	assert.False(t, copies[0].Replaced)
+	assert.Equal(t, []string{"SV", "Default", "@Other", "5678"}, copies[1].To)
+
+	assert.Equal(t, `
+SV =
+{
+    ["Default"] = 
+    {
+        ["@Acct"] = 
+        {
+            ["$AccountWide"] = 
+            {
+                ["color"] = "red",
+            },
+            ["1234"] = 
+            {
+                ["color"] = "red",
+                ["$LastCharacterName"] = "New Alt",
+            },
+        },
+        ["@Other"] = 
+        {
+            ["5678"] = 
+            {
+                ["color"] = "red",
+                ["$LastCharacterName"] = "Other Alt",
+            },
+        },
+    },
+}
+`, string(output))
+}
+
+func TestLuaDocument_CopySettingsErrors(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+	dovahkiin, _ := sampleCharacters(t, document)
+
+	_, _, err := document.CopySettings(dovahkiin, dovahkiin)
+	assert.Error(t, err)
+
+	output, copies, err := document.CopySettings(eso.SavedVarsCharacter{Account: "@nobody", Key: "1"}, dovahkiin)
+	require.NoError(t, err)
+	assert.Empty(t, copies)
+	assert.Equal(t, document.Source, output)
+}
+
+func TestParseSavedVarsOwner(t *testing.T) {
+	tests := map[string][2]string{
+		"@Acct/Char A":       {"@Acct", "Char A"},
+		"@Acct/$AccountWide": {"@Acct", "$AccountWide"},
+		"@Acct":              {"@Acct", ""},
+		"Char A":             {"", "Char A"},
+		"Char/With/Slashes":  {"", "Char/With/Slashes"},
+	}
+
+	for input, expected := range tests {
+		account, character := eso.ParseSavedVarsOwner(input)
+		assert.Equal(t, expected, [2]string{account, character}, input)
+	}
+}
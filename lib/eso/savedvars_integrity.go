@@ -0,0 +1,109 @@
+package eso
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	// shrinkRatio is how small a SavedVariables file may become compared to its last good backup, before it's
+	// considered damaged rather than cleaned up.
+	shrinkRatio = 0.5
+
+	// shrinkMinimum is the size a backup must be for a file to be considered shrunk, as small files change size
+	// proportionally more in normal use.
+	shrinkMinimum = 1024
+)
+
+// SavedVarsProblem is the kind of damage found in a SavedVariables file.
+type SavedVarsProblem int
+
+const (
+	SavedVarsIntact  SavedVarsProblem = iota
+	SavedVarsEmpty                    // The file is empty, or contains no variables
+	SavedVarsCorrupt                  // The file can't be parsed, such as when it was truncated while being written
+	SavedVarsShrunk                   // The file is much smaller than its last good backup
+)
+
+func (P SavedVarsProblem) String() string {
+	switch P {
+	case SavedVarsEmpty:
+		return "empty"
+	case SavedVarsCorrupt:
+		return "corrupt"
+	case SavedVarsShrunk:
+		return "shrunk"
+	default:
+		return "intact"
+	}
+}
+
+// SavedVarsIntegrity is the result of checking a SavedVariables file for damage, such as from the game crashing
+// while writing it.
+type SavedVarsIntegrity struct {
+	Path       string
+	Size       int64
+	Problem    SavedVarsProblem
+	Err        error  // The syntax error, if the file is corrupt (see LuaSyntaxError)
+	Backup     string // The newest backup containing a good copy of the file, or "" if there isn't one
+	BackupData []byte // The good copy of the file within Backup
+}
+
+// CheckSavedVarsIntegrity checks whether the SavedVariables file at path is empty, corrupt, or has shrunk to less than
+// half of its last good copy within backups (newest first, see FindSavedVarsBackups). A copy is good if it contains
+// variables and parses.
+func CheckSavedVarsIntegrity(AppFs afero.Fs, path string, backups []string) (SavedVarsIntegrity, error) {
+	var integrity = SavedVarsIntegrity{Path: path}
+
+	data, err := afero.ReadFile(AppFs, path)
+	if err != nil {
+		return integrity, fmt.Errorf("error reading %q: %w", path, err)
+	}
+
+	integrity.Size = int64(len(data))
+	integrity.Backup, integrity.BackupData = findGoodBackup(AppFs, backups, filepath.Base(path))
+
+	document, err := ParseLua(data)
+
+	switch {
+	case err != nil:
+		integrity.Problem = SavedVarsCorrupt
+		integrity.Err = err
+	case len(document.Assignments) == 0:
+		integrity.Problem = SavedVarsEmpty
+	case len(integrity.BackupData) >= shrinkMinimum && float64(integrity.Size) < float64(len(integrity.BackupData))*shrinkRatio:
+		integrity.Problem = SavedVarsShrunk
+	}
+
+	return integrity, nil
+}
+
+// Restorable returns whether the file may be restored from its last good backup.
+func (I SavedVarsIntegrity) Restorable() bool {
+	return I.Backup != "" && I.Problem != SavedVarsIntact
+}
+
+// MayBeDeliberate returns whether the problem may have been caused on purpose, in which case restoring the file should
+// be confirmed on its own: pruning characters or deleting values legitimately shrinks a file just after backing it up,
+// and restoring it would bring back what was removed.
+func (I SavedVarsIntegrity) MayBeDeliberate() bool {
+	return I.Problem == SavedVarsShrunk
+}
+
+// Returns the newest backup containing a good copy of the named file, along with the copy
+func findGoodBackup(AppFs afero.Fs, backups []string, name string) (string, []byte) {
+	for _, backup := range backups {
+		data, ok, err := readBackupEntry(AppFs, backup, name)
+		if !ok || err != nil {
+			continue
+		}
+
+		if document, err := ParseLua(data); err == nil && len(document.Assignments) > 0 {
+			return backup, data
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,85 @@
+package eso_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSavedVarsIntegrity(t *testing.T) {
+	var fs = afero.NewMemMapFs()
+	var large = "Large_SV =\n{\n" + strings.Repeat("    [\"key\"] = \"value\",\n", 100) + "}\n"
+
+	writeZip(t, fs, "/backups/saved_variables_20240101120000.zip", map[string]string{
+		"Large.lua":   large,
+		"Corrupt.lua": `Corrupt_SV = { ["a"] = 1 }`,
+		"Empty.lua":   `Empty_SV = {}`,
+		"Intact.lua":  `Intact_SV = { ["a"] = 1, ["b"] = 2, ["c"] = 3, ["d"] = 4, ["e"] = 5 }`,
+	})
+	writeZip(t, fs, "/backups/saved_variables_20241001120000.zip", map[string]string{
+		"Large.lua":   large[:100],
+		"Corrupt.lua": "",
+	})
+
+	files := map[string]string{
+		"/sv/Intact.lua":  `Intact_SV = { ["a"] = 1 }`,
+		"/sv/Large.lua":   "Large_SV =\n{\n}\n",
+		"/sv/Corrupt.lua": "Corrupt_SV =\n{\n    [\"a\"] = 1,\n",
+		"/sv/Empty.lua":   "",
+		"/sv/Blank.lua":   "\n",
+	}
+
+	for path, data := range files {
+		require.NoError(t, afero.WriteFile(fs, path, []byte(data), 0644))
+	}
+
+	backups, err := eso.FindSavedVarsBackups(fs, "/backups")
+	require.NoError(t, err)
+
+	tests := []struct {
+		path    string
+		problem eso.SavedVarsProblem
+		backup  string
+	}{
+		{"/sv/Intact.lua", eso.SavedVarsIntact, "/backups/saved_variables_20240101120000.zip"},
+		{"/sv/Large.lua", eso.SavedVarsShrunk, "/backups/saved_variables_20240101120000.zip"},
+		{"/sv/Corrupt.lua", eso.SavedVarsCorrupt, "/backups/saved_variables_20240101120000.zip"},
+		{"/sv/Empty.lua", eso.SavedVarsEmpty, "/backups/saved_variables_20240101120000.zip"},
+		{"/sv/Blank.lua", eso.SavedVarsEmpty, ""},
+	}
+
+	for _, test := range tests {
+		integrity, err := eso.CheckSavedVarsIntegrity(fs, test.path, backups)
+		require.NoError(t, err, test.path)
+
+		assert.Equal(t, test.problem, integrity.Problem, test.path)
+		assert.Equal(t, test.backup, integrity.Backup, test.path)
+		assert.Equal(t, int64(len(files[test.path])), integrity.Size, test.path)
+
+		assert.Equal(t, test.backup != "" && test.problem != eso.SavedVarsIntact, integrity.Restorable(), test.path)
+
+		// Pruning and deleting SavedVariables shrink files too, so they're only restored once confirmed on their own
+		assert.Equal(t, test.problem == eso.SavedVarsShrunk, integrity.MayBeDeliberate(), test.path)
+	}
+
+	integrity, err := eso.CheckSavedVarsIntegrity(fs, "/sv/Corrupt.lua", backups)
+	require.NoError(t, err)
+
+	var syntaxError eso.LuaSyntaxError
+	require.ErrorAs(t, integrity.Err, &syntaxError)
+	assert.Equal(t, 4, syntaxError.Position.Line)
+	assert.Equal(t, `Corrupt_SV = { ["a"] = 1 }`, string(integrity.BackupData))
+
+	// Files which shrink but were small to begin with aren't flagged
+	integrity, err = eso.CheckSavedVarsIntegrity(fs, "/sv/Intact.lua", backups)
+	require.NoError(t, err)
+	assert.Less(t, float64(integrity.Size), float64(len(integrity.BackupData))*0.5)
+	assert.Equal(t, "intact", integrity.Problem.String())
+
+	_, err = eso.CheckSavedVarsIntegrity(fs, "/sv/Missing.lua", backups)
+	assert.Error(t, err)
+}
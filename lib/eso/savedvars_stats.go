@@ -0,0 +1,164 @@
+package eso
+
+import (
+	"sort"
+	"strings"
+)
+
+// wrapperRatio is the share of a table's size which a single field must hold for the table to be considered a mere
+// wrapper around it (such as "Default" or an account), and left out of LuaDocument.LargestSubtrees.
+const wrapperRatio = 0.9
+
+// LuaSubtree is the size of a single table within a Lua file.
+type LuaSubtree struct {
+	Path   []string // The keys leading to the table, starting with the variable name
+	Size   int      // The number of bytes the table takes up, including its key
+	Fields int      // The number of fields directly within the table
+}
+
+// SavedVarsAccountUsage is the size of an account's data within a SavedVariables file.
+type SavedVarsAccountUsage struct {
+	Account     string
+	Size        int                  // The size of all of the account's data
+	AccountWide int                  // The size of the account's "$AccountWide" data
+	Characters  []SavedVarsCharacter // Every character's data, largest first
+}
+
+// Subtrees returns the size of every table in the document, in source order.
+// Sizes are measured in the source, which is how large the game writes them.
+func (D LuaDocument) Subtrees() []LuaSubtree {
+	var subtrees []LuaSubtree
+
+	D.walkSubtrees(func(subtree LuaSubtree, _ bool) {
+		subtrees = append(subtrees, subtree)
+	})
+
+	return subtrees
+}
+
+// LargestSubtrees returns the n largest tables below the top-level variables, largest first. Tables which only wrap a
+// single larger table (holding nearly all of their size) are left out, as their child is the one worth reporting.
+func (D LuaDocument) LargestSubtrees(n int) []LuaSubtree {
+	var largest []LuaSubtree
+
+	D.walkSubtrees(func(subtree LuaSubtree, wrapper bool) {
+		if len(subtree.Path) >= 2 && !wrapper {
+			largest = append(largest, subtree)
+		}
+	})
+
+	sort.SliceStable(largest, func(i, j int) bool { return largest[i].Size > largest[j].Size })
+
+	if len(largest) > n {
+		largest = largest[:max(n, 0)]
+	}
+
+	return largest
+}
+
+// Calls fn with every table in the document, in source order, and whether it's a wrapper around a single table
+func (D LuaDocument) walkSubtrees(fn func(subtree LuaSubtree, wrapper bool)) {
+	for _, field := range D.Table().Fields {
+		D.walkSubtree(field, []string{field.KeyString()}, fn)
+	}
+}
+
+// Walks field and the tables within it, if it's a table
+func (D LuaDocument) walkSubtree(field *LuaField, path []string, fn func(subtree LuaSubtree, wrapper bool)) {
+	if field.Value.Kind != LuaTable {
+		return
+	}
+
+	var subtree = LuaSubtree{Path: path, Size: D.fieldSize(field), Fields: len(field.Value.Fields)}
+	var largestChild int
+
+	for _, child := range field.Value.Fields {
+		if child.Value.Kind == LuaTable {
+			largestChild = max(largestChild, D.fieldSize(child))
+		}
+	}
+
+	fn(subtree, float64(largestChild) >= float64(subtree.Size)*wrapperRatio)
+
+	for _, child := range field.Value.Fields {
+		D.walkSubtree(child, append(path[:len(path):len(path)], child.KeyString()), fn)
+	}
+}
+
+// AccountUsage returns the size of each account's data in the document, and of each of its characters.
+// Accounts found under several variables or namespaces are combined.
+func (D LuaDocument) AccountUsage() []SavedVarsAccountUsage {
+	var usage []SavedVarsAccountUsage
+	var index = make(map[string]int)
+
+	for _, account := range D.accounts() {
+		var name = account.field.KeyString()
+
+		if _, ok := index[name]; !ok {
+			index[name] = len(usage)
+			usage = append(usage, SavedVarsAccountUsage{Account: name})
+		}
+
+		var u = &usage[index[name]]
+
+		u.Size += D.fieldSize(account.field)
+
+		if accountWide := account.field.Value.Field(AccountWide); accountWide != nil {
+			u.AccountWide += D.fieldSize(accountWide)
+		}
+	}
+
+	for _, character := range D.Characters() {
+		var u = &usage[index[character.Account]]
+
+		u.Characters = append(u.Characters, character)
+	}
+
+	for i := range usage {
+		sort.SliceStable(usage[i].Characters, func(a, b int) bool {
+			return usage[i].Characters[a].Size > usage[i].Characters[b].Size
+		})
+	}
+
+	return usage
+}
+
+// The size of a field, including its key, separator, and the line it's on
+func (D LuaDocument) fieldSize(field *LuaField) int {
+	edit := D.DeleteField(field)
+	return edit.End - edit.Start
+}
+
+// SavedVarsDeclaredBy returns the keys of the installed AddOns which declare the given SavedVariables variable in their
+// manifest (## SavedVariables: Name), sorted.
+func (A AddOns) SavedVarsDeclaredBy(variable string) []string {
+	var keys []string
+
+	for _, key := range A.Keys() {
+		for _, declared := range A[key].SavedVariables {
+			if declared == variable {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+
+	return keys
+}
+
+// SavedVarsOwner returns the key of the installed AddOn which owns a SavedVariables file. The game names each file
+// after the AddOn which declared its variables, so that AddOn is the owner if it's installed; otherwise, the owner is
+// the first AddOn declaring any of the file's variables (e.g. after an AddOn was renamed).
+func (A AddOns) SavedVarsOwner(file string, variables []string) (string, bool) {
+	if addon, ok := A.Find(strings.TrimSuffix(file, ".lua")); ok {
+		return addon.Key(), true
+	}
+
+	for _, variable := range variables {
+		if keys := A.SavedVarsDeclaredBy(variable); len(keys) > 0 {
+			return keys[0], true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,168 @@
+package eso_test
+
+import (
+	"testing"
+
+	"github.com/dyoung522/esotools/lib/eso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLuaDocument_Subtrees(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	var paths []string
+	for _, subtree := range document.Subtrees() {
+		paths = append(paths, eso.FormatLuaPath(subtree.Path))
+	}
+
+	assert.Equal(t, []string{
+		"MyAddon_SavedVariables",
+		"MyAddon_SavedVariables.Default",
+		"MyAddon_SavedVariables.Default.@dyoung522",
+		"MyAddon_SavedVariables.Default.@dyoung522.$AccountWide",
+		"MyAddon_SavedVariables.Default.@dyoung522.$AccountWide.position",
+		"MyAddon_SavedVariables.Default.@dyoung522.$AccountWide.favorites",
+		"MyAddon_SavedVariables.Default.@dyoung522.8798292047123456",
+		"MyAddon_SavedVariables.Default.@dyoung522.8798292047654321",
+		"MyAddon_Globals",
+	}, paths)
+
+	subtrees := document.Subtrees()
+	assert.Equal(t, len(document.Source), subtrees[0].Size+subtrees[len(subtrees)-1].Size)
+	assert.Equal(t, 3, subtrees[2].Fields)
+
+	// Each table's size is exactly the bytes it takes up in the file
+	favorites := subtrees[5]
+	assert.Equal(t, len(`                ["favorites"] = 
+                {
+                    [1] = "Vivec City",
+                    [2] = "Wayrest",
+                    [3] = "Alinor",
+                },
+`), favorites.Size)
+}
+
+func TestLuaDocument_LargestSubtrees(t *testing.T) {
+	document := parseSample(t, "MyAddon.lua")
+
+	largest := document.LargestSubtrees(3)
+	require.Len(t, largest, 3)
+
+	// "Default" only wraps the account, so it's skipped
+	assert.Equal(t, "MyAddon_SavedVariables.Default.@dyoung522", eso.FormatLuaPath(largest[0].Path))
+	assert.Equal(t, "MyAddon_SavedVariables.Default.@dyoung522.$AccountWide", eso.FormatLuaPath(largest[1].Path))
+	assert.Equal(t, "MyAddon_SavedVariables.Default.@dyoung522.8798292047654321", eso.FormatLuaPath(largest[2].Path))
+	assert.GreaterOrEqual(t, largest[0].Size, largest[1].Size)
+	assert.GreaterOrEqual(t, largest[1].Size, largest[2].Size)
+
+	assert.Len(t, document.LargestSubtrees(100), 6)
+	assert.Empty(t, document.LargestSubtrees(0))
+	assert.Empty(t, document.LargestSubtrees(-1))
+}
+
+func TestLuaDocument_AccountUsage(t *testing.T) {
+	document := parseLua(t, `
+SV =
+{
+    ["Default"] = 
+    {
+        ["@one"] = 
+        {
+            ["$AccountWide"] = 
+            {
+                ["a"] = 1,
+            },
+            ["1234"] = 
+            {
+                ["$LastCharacterName"] = "Small",
+            },
+            ["5678"] = 
+            {
+                ["$LastCharacterName"] = "Large",
+                ["data"] = "a much longer string than the other",
+            },
+        },
+        ["@two"] = 
+        {
+        },
+    },
+    ["Other"] = 
+    {
+        ["@one"] = 
+        {
+            ["1234"] = 
+            {
+            },
+        },
+    },
+}
+`)
+
+	usage := document.AccountUsage()
+	require.Len(t, usage, 2)
+
+	assert.Equal(t, "@one", usage[0].Account)
+	assert.Greater(t, usage[0].AccountWide, 0)
+	require.Len(t, usage[0].Characters, 3)
+	assert.Equal(t, "Large", usage[0].Characters[0].Name)
+	assert.Equal(t, "Small", usage[0].Characters[1].Name)
+	assert.Equal(t, []string{"SV", "Other", "@one", "1234"}, usage[0].Characters[2].Path)
+
+	var characters int
+	for _, character := range usage[0].Characters {
+		characters += character.Size
+	}
+	assert.Greater(t, usage[0].Size, characters+usage[0].AccountWide)
+
+	assert.Equal(t, "@two", usage[1].Account)
+	assert.Equal(t, 0, usage[1].AccountWide)
+	assert.Empty(t, usage[1].Characters)
+}
+
+func savedVarsAddOns(t *testing.T) eso.AddOns {
+	t.Helper()
+
+	addons, errs := eso.GetAddOns(addOnsFs(t, map[string]string{
+		"AddOns/MyAddon/MyAddon.txt":   "## Title: My Addon\n## SavedVariables: MyAddon_SV MyAddon_Globals\n",
+		"AddOns/Renamed/Renamed.txt":   "## Title: Renamed\n## SavedVariables: OldName_SV\n",
+		"AddOns/Shared/Shared.txt":     "## Title: Shared\n## SavedVariables: MyAddon_Globals\n",
+		"AddOns/NoSaved/NoSaved.addon": "## Title: No SavedVariables\n",
+	}))
+	require.Empty(t, errs)
+
+	return addons
+}
+
+func TestAddOns_SavedVarsDeclaredBy(t *testing.T) {
+	addons := savedVarsAddOns(t)
+
+	assert.Equal(t, []string{"MyAddon"}, addons.SavedVarsDeclaredBy("MyAddon_SV"))
+	assert.Equal(t, []string{"MyAddon", "Shared"}, addons.SavedVarsDeclaredBy("MyAddon_Globals"))
+	assert.Empty(t, addons.SavedVarsDeclaredBy("myaddon_sv"))
+	assert.Empty(t, addons.SavedVarsDeclaredBy("Unknown_SV"))
+}
+
+func TestAddOns_SavedVarsOwner(t *testing.T) {
+	addons := savedVarsAddOns(t)
+
+	tests := []struct {
+		file      string
+		variables []string
+		owner     string
+	}{
+		{"MyAddon.lua", []string{"MyAddon_SV"}, "MyAddon"},
+		{"NoSaved.lua", []string{"Anything"}, "NoSaved"},
+		{"OldName.lua", []string{"OldName_SV"}, "Renamed"},
+		{"Gone.lua", []string{"Gone_SV", "MyAddon_Globals"}, "MyAddon"},
+	}
+
+	for _, test := range tests {
+		owner, ok := addons.SavedVarsOwner(test.file, test.variables)
+		assert.True(t, ok, test.file)
+		assert.Equal(t, test.owner, owner, test.file)
+	}
+
+	_, ok := addons.SavedVarsOwner("Gone.lua", []string{"Gone_SV"})
+	assert.False(t, ok)
+}
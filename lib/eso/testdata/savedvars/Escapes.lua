@@ -0,0 +1,16 @@
+Escapes_SavedVariables =
+{
+    ["quote"] = "She said \"hello\"",
+    ["backslash"] = "C:\\Users\\Public",
+    ["newline"] = "line one\nline two",
+    ["tab"] = "a\tb",
+    ["decimal"] = "\65\066\0067",
+    ["hex"] = "\x41\x42",
+    ["unicode"] = "\u{263A} Ærøskøbing",
+    ["single"] = 'it\'s',
+    ["continued"] = "first\
+second",
+    ["long"] = [[
+raw \n text]],
+    ["empty"] = "",
+}
@@ -0,0 +1,10 @@
+-- Hand edited file, using Lua syntax the game never writes itself
+--[[ long
+comment ]]
+Lenient_SavedVariables = {
+    name = "bare key"; -- trailing comment
+    list = { "a", "b", true, nil, 3 },
+    ["mixed"] = { 10, ["key"] = "value", 20 },
+    nested = { deeper = { deepest = {} } }
+};
+Second = "plain string"
@@ -0,0 +1,44 @@
+MyAddon_SavedVariables =
+{
+    ["Default"] = 
+    {
+        ["@dyoung522"] = 
+        {
+            ["$AccountWide"] = 
+            {
+                ["version"] = 3,
+                ["enabled"] = true,
+                ["debug"] = false,
+                ["scale"] = 0.8500000000,
+                ["position"] = 
+                {
+                    ["x"] = -125.5000000000,
+                    ["y"] = 340,
+                },
+                ["favorites"] = 
+                {
+                    [1] = "Vivec City",
+                    [2] = "Wayrest",
+                    [3] = "Alinor",
+                },
+            },
+            ["8798292047123456"] = 
+            {
+                ["version"] = 3,
+                ["$LastCharacterName"] = "Dovahkiin",
+                ["enabled"] = true,
+            },
+            ["8798292047654321"] = 
+            {
+                ["version"] = 3,
+                ["$LastCharacterName"] = "Ærøn the Swift",
+                ["enabled"] = false,
+            },
+        },
+    },
+}
+MyAddon_Globals =
+{
+    ["lastRun"] = 1727798400,
+    ["motd"] = "Welcome to |cFF0000Tamriel|r!",
+}
@@ -0,0 +1,16 @@
+Numbers_SavedVariables =
+{
+    ["integer"] = 42,
+    ["negative"] = -17,
+    ["float"] = 3.1415926536,
+    ["exponent"] = 1.5e+15,
+    ["smallExponent"] = 2.5e-07,
+    ["hex"] = 0xFF,
+    ["inf"] = 1.#INF,
+    ["negativeInf"] = -1.#INF,
+    ["nan"] = -1.#IND,
+    ["bigId"] = 8798292047123456789,
+    [1] = "one",
+    [2.5] = "two and a half",
+    [-3] = "minus three",
+}